@@ -0,0 +1,109 @@
+package time_intervals
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResourceInput is the availability input of one independent resource (a room, a person)
+type ResourceInput struct {
+	ID        string
+	Available []Interval
+	Blocked   []Interval
+	StartDay  time.Time // when StartDay and EndDay are set the free intervals are also split with IntervalsForEachDayInRange
+	EndDay    time.Time
+}
+
+type ResourceAvailability struct {
+	ID   string
+	Free []Interval
+	Days []DayIntervals
+	Err  error
+}
+
+// BatchError collects the errors of every resource that failed, keyed by resource id
+type BatchError struct {
+	Failed map[string]error
+}
+
+func (e *BatchError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("%s: %v", id, e.Failed[id])
+	}
+	return fmt.Sprintf("%d resources failed: %s", len(ids), strings.Join(msgs, "; "))
+}
+
+// ComputeAvailability runs SubstractBlockedIntervals (and IntervalsForEachDayInRange when a day range is given)
+// for every resource on a pool of at most workers goroutines, GOMAXPROCS when workers is not positive.
+// The results are returned in the order of the inputs. A failing resource does not stop the others, its error is
+// stored in its result and all of them are returned together as a *BatchError. When ctx is cancelled the resources
+// that were not processed yet fail with the context error.
+func ComputeAvailability(ctx context.Context, inputs []ResourceInput, workers int) ([]ResourceAvailability, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]ResourceAvailability, len(inputs))
+	done := make([]bool, len(inputs))
+	jobs := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[k] = computeResourceAvailability(inputs[k])
+				done[k] = true
+			}
+		}()
+	}
+
+feed:
+	for k := range inputs {
+		select {
+		case jobs <- k:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := map[string]error{}
+	for k := range results {
+		if !done[k] {
+			results[k] = ResourceAvailability{ID: inputs[k].ID, Err: ctx.Err()}
+		}
+		if results[k].Err != nil {
+			failed[results[k].ID] = results[k].Err
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchError{Failed: failed}
+	}
+	return results, nil
+}
+
+func computeResourceAvailability(in ResourceInput) ResourceAvailability {
+	r := ResourceAvailability{ID: in.ID}
+	r.Free = SubstractBlockedIntervals(in.Available, in.Blocked)
+	if !in.StartDay.IsZero() || !in.EndDay.IsZero() {
+		r.Days, r.Err = IntervalsForEachDayInRange(r.Free, in.StartDay, in.EndDay)
+	}
+	return r
+}
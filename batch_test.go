@@ -0,0 +1,56 @@
+package time_intervals
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ComputeAvailability(t *testing.T) {
+	inputs := []ResourceInput{}
+	for k := 0; k < 100; k++ {
+		inputs = append(inputs, ResourceInput{
+			ID:        fmt.Sprintf("room-%d", k),
+			Available: []Interval{testDHInterval(0, 9, 0, 17), testDHInterval(1, 9, 1, 17)},
+			Blocked:   []Interval{testDHInterval(0, 10, 0, 10+k%5)},
+			StartDay:  baseTime,
+			EndDay:    baseTime.AddDate(0, 0, 1),
+		})
+	}
+	// an invalid day range only fails its own resource
+	inputs[42].EndDay = baseTime.AddDate(0, 0, -1)
+
+	results, err := ComputeAvailability(context.Background(), inputs, 4)
+	assert.Equal(t, 100, len(results))
+
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok, "expected a batch error, got %v", err)
+	assert.Equal(t, 1, len(batchErr.Failed))
+	assert.Error(t, batchErr.Failed["room-42"])
+	assert.Error(t, results[42].Err)
+
+	for k, r := range results {
+		assert.Equal(t, inputs[k].ID, r.ID)
+		assertSameIntervals(t, SubstractBlockedIntervals(inputs[k].Available, inputs[k].Blocked), r.Free)
+		if k != 42 {
+			assert.NoError(t, r.Err)
+			assert.Equal(t, 2, len(r.Days))
+		}
+	}
+}
+
+func Test_ComputeAvailability_Cancelled(t *testing.T) {
+	inputs := []ResourceInput{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := ComputeAvailability(ctx, inputs, 2)
+	assert.Error(t, err)
+	assert.Equal(t, 3, len(results))
+	for _, r := range results {
+		assert.Equal(t, context.Canceled, r.Err)
+	}
+}
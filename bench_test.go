@@ -0,0 +1,69 @@
+package time_intervals
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var benchmarkSizes = []int{1000, 10000, 100000, 1000000}
+
+// n available intervals and n/2 blocked ones spread over roughly a year, overlapping each other
+func benchmarkInput(n int) (available []Interval, blocked []Interval) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() Interval {
+		s := baseTime.Add(time.Duration(rnd.Int63n(int64(n)*30)) * time.Minute)
+		return Interval{Start: s, End: s.Add(time.Duration(15+rnd.Intn(120)) * time.Minute)}
+	}
+	for i := 0; i < n; i++ {
+		available = append(available, random())
+	}
+	for i := 0; i < n/2; i++ {
+		blocked = append(blocked, random())
+	}
+	return available, blocked
+}
+
+func Benchmark_SubstractBlockedIntervals(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		available, blocked := benchmarkInput(n)
+		b.Run(fmt.Sprintf("heap/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SubstractBlockedIntervals(available, blocked)
+			}
+		})
+		b.Run(fmt.Sprintf("sort/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				AppendSubstractBlockedIntervals(nil, available, blocked)
+			}
+		})
+		b.Run(fmt.Sprintf("sort_reused/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			s := Sweeper{}
+			dst := []Interval{}
+			for i := 0; i < b.N; i++ {
+				dst = s.AppendSubstractBlockedIntervals(dst[:0], available, blocked)
+			}
+		})
+
+		orderedAvailable := MergeAndReturnNonOverlappingIntervals(available)
+		orderedBlocked := MergeAndReturnNonOverlappingIntervals(blocked)
+		b.Run(fmt.Sprintf("linear_ordered/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SubstractBlockedIntervals(orderedAvailable, orderedBlocked)
+			}
+		})
+		b.Run(fmt.Sprintf("merge_ordered_reused/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			s := Sweeper{}
+			dst := []Interval{}
+			for i := 0; i < b.N; i++ {
+				dst = s.AppendSubstractBlockedIntervals(dst[:0], orderedAvailable, orderedBlocked)
+			}
+		})
+	}
+}
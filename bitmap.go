@@ -0,0 +1,197 @@
+package time_intervals
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+var ErrBitmapMismatch = errors.New("Bitmaps must have the same origin, granularity and length")
+
+// Bitmap is a timeline cut in slots of fixed granularity starting at Origin, bit k is set when the slot
+// [Origin + k*Granularity, Origin + (k+1)*Granularity) is free. A week at 15 minutes fits in 11 words, so
+// intersecting the availability of many people is a few AND instructions each.
+type Bitmap struct {
+	Origin      time.Time
+	Granularity time.Duration
+	Len         int // number of slots, the bits after Len are always zero
+	Bits        []uint64
+}
+
+// NewBitmap returns an empty bitmap with as many whole slots as fit in the window
+func NewBitmap(window Interval, granularity time.Duration) (*Bitmap, error) {
+	if granularity <= 0 {
+		return nil, errors.New("Granularity must be positive")
+	}
+	if window.End.Before(window.Start) {
+		return nil, ErrInvalidInterval
+	}
+	n := int(window.End.Sub(window.Start) / granularity)
+	return &Bitmap{
+		Origin:      window.Start,
+		Granularity: granularity,
+		Len:         n,
+		Bits:        make([]uint64, (n+63)/64),
+	}, nil
+}
+
+// BitmapFromIntervals sets the slots of the window covered by the intervals, the mode decides the partially covered
+// slots like in Snap. Use SnapShrink for available time, it only sets fully covered slots, and SnapExpand for blocked
+// time, it sets every slot the intervals touch, so that available.AndNot(blocked) never reports a partly blocked slot
+// as free. The intervals are merged first, so they do not need to be ordered or disjoint. Empty intervals are ignored.
+func BitmapFromIntervals(a []Interval, window Interval, granularity time.Duration, mode SnapMode) (*Bitmap, error) {
+	b, err := NewBitmap(window, granularity)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range MergeAndReturnNonOverlappingIntervals(a) {
+		var from, to int
+		switch mode {
+		case SnapShrink:
+			from, to = b.slotCeil(i.Start), b.slotFloor(i.End)
+		case SnapExpand:
+			from, to = b.slotFloor(i.Start), b.slotCeil(i.End)
+		case SnapFloor:
+			from, to = b.slotFloor(i.Start), b.slotFloor(i.End)
+		case SnapCeil:
+			from, to = b.slotCeil(i.Start), b.slotCeil(i.End)
+		case SnapRound:
+			// halfway values go forward like in RoundTime
+			from, to = b.slotFloor(i.Start.Add(granularity/2)), b.slotFloor(i.End.Add(granularity/2))
+		default:
+			return nil, errors.New(fmt.Sprintf("Unknown snap mode %q", mode))
+		}
+		b.setRange(max(from, 0), min(to, b.Len))
+	}
+	return b, nil
+}
+
+// Intervals returns the ordered disjoint intervals of set slots, adjacent slots are merged
+func (b *Bitmap) Intervals() []Interval {
+	r := []Interval{}
+	for from := b.nextSet(0); from < b.Len; {
+		to := b.nextClear(from)
+		r = append(r, Interval{Start: b.slotStart(from), End: b.slotStart(to)})
+		from = b.nextSet(to)
+	}
+	return r
+}
+
+// Has returns true if the slot k is set
+func (b *Bitmap) Has(k int) bool {
+	return k >= 0 && k < b.Len && b.Bits[k/64]&(1<<(k%64)) != 0
+}
+
+// Count returns the number of set slots
+func (b *Bitmap) Count() int {
+	c := 0
+	for _, w := range b.Bits {
+		c += bits.OnesCount64(w)
+	}
+	return c
+}
+
+// And returns the slots set in both bitmaps
+func (b *Bitmap) And(o *Bitmap) (*Bitmap, error) {
+	return b.combine(o, func(x, y uint64) uint64 { return x & y })
+}
+
+// Or returns the slots set in either bitmap
+func (b *Bitmap) Or(o *Bitmap) (*Bitmap, error) {
+	return b.combine(o, func(x, y uint64) uint64 { return x | y })
+}
+
+// AndNot returns the slots set in b but not in o, the bitmap version of SubstractBlockedIntervals
+func (b *Bitmap) AndNot(o *Bitmap) (*Bitmap, error) {
+	return b.combine(o, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// FindRun returns the first interval of n consecutive set slots, e.g. the earliest hour every attendee is free
+func (b *Bitmap) FindRun(n int) (Interval, bool) {
+	if n <= 0 {
+		return Interval{}, false
+	}
+	for from := b.nextSet(0); from < b.Len; {
+		to := b.nextClear(from)
+		if to-from >= n {
+			return Interval{Start: b.slotStart(from), End: b.slotStart(from + n)}, true
+		}
+		from = b.nextSet(to)
+	}
+	return Interval{}, false
+}
+
+func (b *Bitmap) combine(o *Bitmap, op func(x, y uint64) uint64) (*Bitmap, error) {
+	if !b.Origin.Equal(o.Origin) || b.Granularity != o.Granularity || b.Len != o.Len {
+		return nil, ErrBitmapMismatch
+	}
+	r := &Bitmap{Origin: b.Origin, Granularity: b.Granularity, Len: b.Len, Bits: make([]uint64, len(b.Bits))}
+	for k := range b.Bits {
+		r.Bits[k] = op(b.Bits[k], o.Bits[k])
+	}
+	return r, nil
+}
+
+func (b *Bitmap) slotStart(k int) time.Time {
+	return b.Origin.Add(time.Duration(k) * b.Granularity)
+}
+
+// index of the first slot starting at or after t
+func (b *Bitmap) slotCeil(t time.Time) int {
+	d := t.Sub(b.Origin)
+	k := int(d / b.Granularity)
+	if d > 0 && d%b.Granularity != 0 {
+		k++
+	}
+	return k
+}
+
+// index of the first slot ending after t
+func (b *Bitmap) slotFloor(t time.Time) int {
+	d := t.Sub(b.Origin)
+	k := int(d / b.Granularity)
+	if d < 0 && d%b.Granularity != 0 {
+		k--
+	}
+	return k
+}
+
+// sets the slots [from, to)
+func (b *Bitmap) setRange(from, to int) {
+	for k := from; k < to; {
+		w, bit := k/64, k%64
+		n := min(64-bit, to-k)
+		mask := ^uint64(0)
+		if n < 64 {
+			mask = (1<<n - 1) << bit
+		}
+		b.Bits[w] |= mask
+		k += n
+	}
+}
+
+// returns the first set slot at or after k, or Len if there is none
+func (b *Bitmap) nextSet(k int) int {
+	for k < b.Len {
+		w := b.Bits[k/64] >> (k % 64)
+		if w != 0 {
+			return min(k+bits.TrailingZeros64(w), b.Len)
+		}
+		k = (k/64 + 1) * 64
+	}
+	return b.Len
+}
+
+// returns the first unset slot at or after k, or Len if there is none
+func (b *Bitmap) nextClear(k int) int {
+	for k < b.Len {
+		w := ^b.Bits[k/64] >> (k % 64)
+		if w != 0 {
+			return min(k+bits.TrailingZeros64(w), b.Len)
+		}
+		k = (k/64 + 1) * 64
+	}
+	return b.Len
+}
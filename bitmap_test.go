@@ -0,0 +1,145 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BitmapFromIntervals(t *testing.T) {
+	// intervals: 10-70, 90-105 and one outside of the window
+	// slots:      0    1    2    3    4    5    6    7
+	// set:             #    #    #              #
+
+	window := testInterval(0, 120)
+	b, err := BitmapFromIntervals([]Interval{testInterval(10, 70), testInterval(90, 105), testInterval(200, 300)}, window, 15*time.Minute, SnapShrink)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, b.Len)
+	assert.Equal(t, 4, b.Count())
+	assert.True(t, b.Has(1) && b.Has(3) && b.Has(6))
+	assert.False(t, b.Has(0) || b.Has(4) || b.Has(8))
+
+	r := b.Intervals()
+	assert.Equal(t, 2, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(15, 60), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(90, 105), r[1]))
+}
+
+func Test_Bitmap_Mismatch(t *testing.T) {
+	a, _ := NewBitmap(testInterval(0, 120), 15*time.Minute)
+	b, _ := NewBitmap(testInterval(0, 120), 30*time.Minute)
+	c, _ := NewBitmap(testInterval(15, 135), 15*time.Minute)
+
+	_, err := a.And(b)
+	assert.Equal(t, ErrBitmapMismatch, err)
+	_, err = a.AndNot(c)
+	assert.Equal(t, ErrBitmapMismatch, err)
+
+	_, err = NewBitmap(testInterval(0, 120), 0)
+	assert.Error(t, err)
+}
+
+func Test_Bitmap_FindRun(t *testing.T) {
+	// a week of 15 minute slots, so runs cross the 64 bit words
+	window := Interval{Start: baseTime, End: baseTime.AddDate(0, 0, 7)}
+	available := []Interval{
+		testDHInterval(0, 9, 0, 10),
+		testDHInterval(0, 14, 0, 17),
+		testDHInterval(1, 23, 2, 3),
+	}
+	b, err := BitmapFromIntervals(available, window, 15*time.Minute, SnapShrink)
+	assert.NoError(t, err)
+
+	r, ok := b.FindRun(4)
+	assert.True(t, ok)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 9, 0, 10), r))
+
+	r, ok = b.FindRun(6)
+	assert.True(t, ok)
+	assert.Equal(t, "", intervalsDiff(Interval{Start: testDHInterval(0, 14, 0, 14).Start, End: testDHInterval(0, 14, 0, 14).Start.Add(90 * time.Minute)}, r))
+
+	r, ok = b.FindRun(16)
+	assert.True(t, ok)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(1, 23, 2, 3), r))
+
+	_, ok = b.FindRun(17)
+	assert.False(t, ok)
+}
+
+func Test_BitmapFromIntervals_PartialSlots(t *testing.T) {
+	window := testInterval(0, 60)
+	const g = 15 * time.Minute
+
+	// a slot that is only partly blocked is not free
+	a, _ := BitmapFromIntervals([]Interval{testInterval(0, 60)}, window, g, SnapShrink)
+	b, _ := BitmapFromIntervals([]Interval{testInterval(5, 10)}, window, g, SnapExpand)
+	free, err := a.AndNot(b)
+	assert.NoError(t, err)
+	assertSameIntervals(t, []Interval{testInterval(15, 60)}, free.Intervals())
+	r, ok := free.FindRun(4)
+	assert.False(t, ok, "found %v", r)
+
+	// touching intervals cover the slot together
+	a, _ = BitmapFromIntervals([]Interval{testInterval(10, 30), testInterval(0, 10)}, window, g, SnapShrink)
+	assertSameIntervals(t, []Interval{testInterval(0, 30)}, a.Intervals())
+
+	a, _ = BitmapFromIntervals([]Interval{testInterval(10, 38)}, window, g, SnapRound)
+	assertSameIntervals(t, []Interval{testInterval(15, 45)}, a.Intervals())
+
+	_, err = BitmapFromIntervals([]Interval{testInterval(10, 37)}, window, g, SnapMode("nearest"))
+	assert.Error(t, err)
+}
+
+func Test_Bitmap_MatchesSubstractBlockedIntervals(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	const g = 15 * time.Minute
+	const slots = 7 * 24 * 4
+	window := testInterval(0, slots*15)
+	// a mix of aligned intervals, unaligned ones and pairs that only cover a slot together
+	randomIntervals := func(n int) []Interval {
+		r := []Interval{}
+		for i := 0; i < n; i++ {
+			s := rnd.Intn(slots)
+			switch rnd.Intn(3) {
+			case 0:
+				r = append(r, testInterval(s*15, (s+1+rnd.Intn(40))*15))
+			case 1:
+				start := s*15 + rnd.Intn(15)
+				r = append(r, testInterval(start, start+1+rnd.Intn(600)))
+			case 2:
+				start, middle := s*15, s*15+1+rnd.Intn(30)
+				r = append(r, testInterval(start, middle), testInterval(middle, middle+1+rnd.Intn(30)))
+			}
+		}
+		return r
+	}
+
+	for run := 0; run < 300; run++ {
+		available := randomIntervals(rnd.Intn(30))
+		blocked := randomIntervals(rnd.Intn(30))
+
+		a, err := BitmapFromIntervals(available, window, g, SnapShrink)
+		assert.NoError(t, err)
+		b, err := BitmapFromIntervals(blocked, window, g, SnapExpand)
+		assert.NoError(t, err)
+
+		// the free slots are the ones fully inside the free time
+		free, err := a.AndNot(b)
+		assert.NoError(t, err)
+		expected := clipIntervals(Snap(SubstractBlockedIntervals(available, blocked), SnapShrink, g, time.UTC), window)
+		if !assertSameIntervals(t, expected, free.Intervals()) {
+			return
+		}
+
+		a2, _ := BitmapFromIntervals(blocked, window, g, SnapShrink)
+		both, _ := a.And(a2)
+		assertSameIntervals(t, clipIntervals(Snap(Intersect(available, blocked), SnapShrink, g, time.UTC), window), both.Intervals())
+
+		b2, _ := BitmapFromIntervals(available, window, g, SnapExpand)
+		either, _ := b2.Or(b)
+		touched := MergeAndReturnNonOverlappingIntervals(Snap(Union(available, blocked), SnapExpand, g, time.UTC))
+		assertSameIntervals(t, clipIntervals(touched, window), either.Intervals())
+	}
+}
@@ -0,0 +1,95 @@
+package time_intervals
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// the context is checked once every checkEvery steps of a loop
+const checkEvery = 1024
+
+// Limits guards the context aware operations against inputs that would keep them busy for too long.
+// A zero value means no limit.
+type Limits struct {
+	MaxInputIntervals int
+	MaxResults        int
+}
+
+// LimitError is returned when an operation would go over one of its Limits
+type LimitError struct {
+	What  string // "input" or "results"
+	Limit int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("number of %s intervals is over the limit of %d", e.What, e.Limit)
+}
+
+// SubstractBlockedIntervalsContext is SubstractBlockedIntervals checking ctx during the sweep.
+// On cancellation it returns the context error, when a limit is exceeded a *LimitError.
+// ctx is only checked before and after the endpoints are sorted, not during the sort, so with millions of unordered
+// intervals a cancellation can wait for the sort to finish. Use Limits.MaxInputIntervals to bound that time.
+func SubstractBlockedIntervalsContext(ctx context.Context, available []Interval, blocked []Interval, limits Limits) ([]Interval, error) {
+	if limits.MaxInputIntervals > 0 && len(available)+len(blocked) > limits.MaxInputIntervals {
+		return []Interval{}, &LimitError{What: "input", Limit: limits.MaxInputIntervals}
+	}
+	if err := ctx.Err(); err != nil {
+		return []Interval{}, err
+	}
+
+	s := Sweeper{}
+	endpoints := s.orderedEndpoints(available, blocked)
+	if err := ctx.Err(); err != nil {
+		return []Interval{}, err
+	}
+
+	r, err := sweepEndpointsContext(ctx, []Interval{}, endpoints, limits.MaxResults)
+	if err != nil {
+		return []Interval{}, err
+	}
+	return r, nil
+}
+
+// MergeAndReturnNonOverlappingIntervalsContext is MergeAndReturnNonOverlappingIntervals checking ctx during the sweep
+func MergeAndReturnNonOverlappingIntervalsContext(ctx context.Context, a []Interval, limits Limits) ([]Interval, error) {
+	return SubstractBlockedIntervalsContext(ctx, a, []Interval{}, limits)
+}
+
+// SplitInFixedIntervalsContext is SplitInFixedIntervals checking ctx while the slots are generated
+func SplitInFixedIntervalsContext(ctx context.Context, orderedDisjointIntervals []Interval, intervalLengthInMinutes int, limits Limits) ([]Interval, error) {
+	if intervalLengthInMinutes <= 0 {
+		return []Interval{}, errors.New("Interval length must be positive")
+	}
+	if limits.MaxInputIntervals > 0 && len(orderedDisjointIntervals) > limits.MaxInputIntervals {
+		return []Interval{}, &LimitError{What: "input", Limit: limits.MaxInputIntervals}
+	}
+
+	r := []Interval{}
+	var err error
+	l := time.Duration(intervalLengthInMinutes) * time.Minute
+	for _, i := range orderedDisjointIntervals {
+		ok := splitInFixedIntervals(i, l, func(slot Interval) bool {
+			if len(r)%checkEvery == checkEvery-1 {
+				if err = ctx.Err(); err != nil {
+					return false
+				}
+			}
+			if limits.MaxResults > 0 && len(r) == limits.MaxResults {
+				err = &LimitError{What: "results", Limit: limits.MaxResults}
+				return false
+			}
+			r = append(r, slot)
+			return true
+		})
+		if !ok {
+			return []Interval{}, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return []Interval{}, err
+	}
+	return r, nil
+}
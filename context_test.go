@@ -0,0 +1,90 @@
+package time_intervals
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SubstractBlockedIntervalsContext(t *testing.T) {
+	available := []Interval{testInterval(0, 10), testInterval(20, 30)}
+	blocked := []Interval{testInterval(5, 22)}
+
+	r, err := SubstractBlockedIntervalsContext(context.Background(), available, blocked, Limits{})
+	assert.NoError(t, err)
+	assertSameIntervals(t, SubstractBlockedIntervals(available, blocked), r)
+
+	_, err = SubstractBlockedIntervalsContext(context.Background(), available, blocked, Limits{MaxInputIntervals: 2})
+	assert.Equal(t, &LimitError{What: "input", Limit: 2}, err)
+
+	_, err = SubstractBlockedIntervalsContext(context.Background(), available, blocked, Limits{MaxResults: 1})
+	assert.Equal(t, &LimitError{What: "results", Limit: 1}, err)
+
+	r, err = SubstractBlockedIntervalsContext(context.Background(), available, blocked, Limits{MaxResults: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(r))
+}
+
+func Test_SubstractBlockedIntervalsContext_Cancelled(t *testing.T) {
+	available, blocked := benchmarkInput(10000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, err := SubstractBlockedIntervalsContext(ctx, available, blocked, Limits{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, len(r))
+}
+
+// a context that reports cancellation from the n-th call of Err on
+type countdownContext struct {
+	context.Context
+	n     int
+	calls int
+}
+
+func (c *countdownContext) Err() error {
+	c.calls++
+	if c.calls >= c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func Test_SubstractBlockedIntervalsContext_CancelledDuringSweep(t *testing.T) {
+	// 40000 endpoints are checked about 39 times, the two checks before the sweep pass
+	available, blocked := benchmarkInput(10000)
+	ctx := &countdownContext{Context: context.Background(), n: 3}
+
+	r, err := SubstractBlockedIntervalsContext(ctx, available, blocked, Limits{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, len(r))
+	assert.Equal(t, 3, ctx.calls, "the sweep must stop at the first check that sees the cancellation")
+
+	// cancelled at the second check, only the results of the first 2*checkEvery endpoints are there
+	r, err = sweepEndpointsContext(&countdownContext{Context: context.Background(), n: 2}, []Interval{}, (&Sweeper{}).orderedEndpoints(available, blocked), 0)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, len(r) < 2*checkEvery, "got %d results", len(r))
+	assert.NotZero(t, len(r))
+}
+
+func Test_SplitInFixedIntervalsContext(t *testing.T) {
+	// a year of availability in 2 minute slots
+	year := []Interval{{Start: baseTime, End: baseTime.AddDate(1, 0, 0)}}
+
+	_, err := SplitInFixedIntervalsContext(context.Background(), year, 2, Limits{MaxResults: 10000})
+	assert.Equal(t, &LimitError{What: "results", Limit: 10000}, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = SplitInFixedIntervalsContext(ctx, year, 2, Limits{})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = SplitInFixedIntervalsContext(context.Background(), year, 0, Limits{})
+	assert.Error(t, err)
+
+	a := []Interval{testInterval(8*60+15, 11*60+43), testInterval(12*60, 13*60)}
+	r, err := SplitInFixedIntervalsContext(context.Background(), a, 30, Limits{MaxResults: 8})
+	assert.NoError(t, err)
+	assertSameIntervals(t, SplitInFixedIntervals(a, 30), r)
+}
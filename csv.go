@@ -0,0 +1,144 @@
+package time_intervals
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+type CSVFormat struct {
+	StartColumn string         // header of the start column, defaults to "start"
+	EndColumn   string         // header of the end column, defaults to "end"
+	Layout      string         // time layout of the start and end values, defaults to time.RFC3339
+	Location    *time.Location // location of the values without a time zone, defaults to UTC
+}
+
+func (f CSVFormat) withDefaults() CSVFormat {
+	if f.StartColumn == "" {
+		f.StartColumn = "start"
+	}
+	if f.EndColumn == "" {
+		f.EndColumn = "end"
+	}
+	if f.Layout == "" {
+		f.Layout = time.RFC3339
+	}
+	if f.Location == nil {
+		f.Location = time.UTC
+	}
+	return f
+}
+
+// CSVRowError tells which line of the input could not be read
+type CSVRowError struct {
+	Row int
+	Err error
+}
+
+func (e *CSVRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadIntervalsCSV reads intervals from a CSV with a header row, other columns are ignored.
+// A malformed row fails the whole read with a *CSVRowError holding its line number.
+func ReadIntervalsCSV(r io.Reader, f CSVFormat) ([]Interval, error) {
+	f = f.withDefaults()
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return []Interval{}, &CSVRowError{Row: 1, Err: err}
+	}
+	startIdx, endIdx := -1, -1
+	for k, h := range header {
+		switch h {
+		case f.StartColumn:
+			startIdx = k
+		case f.EndColumn:
+			endIdx = k
+		}
+	}
+	if startIdx < 0 || endIdx < 0 {
+		return []Interval{}, &CSVRowError{Row: 1, Err: errors.New(fmt.Sprintf("Missing %q or %q column", f.StartColumn, f.EndColumn))}
+	}
+
+	result := []Interval{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok {
+				return []Interval{}, &CSVRowError{Row: pe.Line, Err: pe.Err}
+			}
+			return []Interval{}, err
+		}
+		row, _ := cr.FieldPos(0)
+
+		start, err := time.ParseInLocation(f.Layout, record[startIdx], f.Location)
+		if err != nil {
+			return []Interval{}, &CSVRowError{Row: row, Err: err}
+		}
+		end, err := time.ParseInLocation(f.Layout, record[endIdx], f.Location)
+		if err != nil {
+			return []Interval{}, &CSVRowError{Row: row, Err: err}
+		}
+		if end.Before(start) {
+			return []Interval{}, &CSVRowError{Row: row, Err: ErrInvalidInterval}
+		}
+		result = append(result, Interval{Start: start, End: end})
+	}
+}
+
+// WriteIntervalsCSV writes one row per interval with the start, the end and the length in minutes
+func WriteIntervalsCSV(w io.Writer, a []Interval, f CSVFormat) error {
+	f = f.withDefaults()
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{f.StartColumn, f.EndColumn, "minutes"}); err != nil {
+		return err
+	}
+	for _, i := range a {
+		if err := cw.Write(csvIntervalFields(i, f)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteDayIntervalsCSV writes the grid returned by IntervalsForEachDayInRange, one row per interval with the date,
+// the day index (CountSinceFirst), the start, the end and the length in minutes. Days without intervals have no rows.
+func WriteDayIntervalsCSV(w io.Writer, days []DayIntervals, f CSVFormat) error {
+	f = f.withDefaults()
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "day", f.StartColumn, f.EndColumn, "minutes"}); err != nil {
+		return err
+	}
+	for _, d := range days {
+		for _, i := range d.OrderedDisjunctIntervals {
+			row := append([]string{d.Date.Format("2006-01-02"), strconv.Itoa(d.CountSinceFirst)}, csvIntervalFields(i, f)...)
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvIntervalFields(i Interval, f CSVFormat) []string {
+	return []string{
+		i.Start.In(f.Location).Format(f.Layout),
+		i.End.In(f.Location).Format(f.Layout),
+		strconv.FormatFloat(i.End.Sub(i.Start).Minutes(), 'f', -1, 64),
+	}
+}
@@ -0,0 +1,72 @@
+package time_intervals
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadIntervalsCSV(t *testing.T) {
+	in := "room,from,to\n" +
+		"a,2018-04-07 00:01,2018-04-07 00:04\n" +
+		"b,2018-04-07 00:10,2018-04-07 00:12\n"
+
+	r, err := ReadIntervalsCSV(strings.NewReader(in), CSVFormat{StartColumn: "from", EndColumn: "to", Layout: "2006-01-02 15:04"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(r))
+	assert.Equal(t, "", intervalsDiff(testInterval(1, 4), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(10, 12), r[1]))
+}
+
+func Test_ReadIntervalsCSV_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	in := "start,end\n2018-04-07 02:01,2018-04-07 02:04\n"
+
+	r, err := ReadIntervalsCSV(strings.NewReader(in), CSVFormat{Layout: "2006-01-02 15:04", Location: loc})
+	assert.NoError(t, err)
+	assert.True(t, testInterval(1, 4).Start.Equal(r[0].Start))
+}
+
+func Test_ReadIntervalsCSV_Errors(t *testing.T) {
+	f := CSVFormat{Layout: "2006-01-02 15:04"}
+
+	_, err := ReadIntervalsCSV(strings.NewReader("from,to\n"), f)
+	assert.Equal(t, 1, err.(*CSVRowError).Row)
+
+	_, err = ReadIntervalsCSV(strings.NewReader("start,end\n2018-04-07 00:01,2018-04-07 00:04\n2018-04-07 00:01,yesterday\n"), f)
+	assert.Equal(t, 3, err.(*CSVRowError).Row)
+
+	_, err = ReadIntervalsCSV(strings.NewReader("start,end\n2018-04-07 00:05,2018-04-07 00:04\n"), f)
+	assert.Equal(t, 2, err.(*CSVRowError).Row)
+	assert.Equal(t, ErrInvalidInterval, err.(*CSVRowError).Err)
+
+	_, err = ReadIntervalsCSV(strings.NewReader("start,end\n2018-04-07 00:01,2018-04-07 00:04\n\n2018-04-07 00:01\n"), f)
+	assert.Equal(t, 4, err.(*CSVRowError).Row)
+}
+
+func Test_WriteIntervalsCSV(t *testing.T) {
+	f := CSVFormat{Layout: "2006-01-02 15:04"}
+	b := bytes.Buffer{}
+	assert.NoError(t, WriteIntervalsCSV(&b, []Interval{testInterval(1, 4), testInterval(10, 12)}, f))
+	assert.Equal(t, "start,end,minutes\n2018-04-07 00:01,2018-04-07 00:04,3\n2018-04-07 00:10,2018-04-07 00:12,2\n", b.String())
+
+	// round trip
+	r, err := ReadIntervalsCSV(&b, f)
+	assert.NoError(t, err)
+	assertSameIntervals(t, []Interval{testInterval(1, 4), testInterval(10, 12)}, r)
+}
+
+func Test_WriteDayIntervalsCSV(t *testing.T) {
+	A := testDHInterval(0, 22, 1, 2)
+	days, err := IntervalsForEachDayInRange([]Interval{A}, A.Start.AddDate(0, 0, -1), A.End)
+	assert.NoError(t, err)
+
+	b := bytes.Buffer{}
+	assert.NoError(t, WriteDayIntervalsCSV(&b, days, CSVFormat{Layout: "15:04:05.000"}))
+	assert.Equal(t, "date,day,start,end,minutes\n"+
+		"2018-04-10,1,22:00:00.000,23:59:59.999,119.99998333333333\n"+
+		"2018-04-11,2,00:00:00.000,02:00:00.000,120\n", b.String())
+}
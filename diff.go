@@ -0,0 +1,30 @@
+package time_intervals
+
+// Diff compares two versions of a free/busy set. Both sides are merged first, so only real changes in the covered time
+// are reported: added is the time covered by newSet but not by oldSet, removed is the time covered by oldSet only.
+func Diff(oldSet []Interval, newSet []Interval) (added []Interval, removed []Interval) {
+	o := MergeAndReturnNonOverlappingIntervals(oldSet)
+	n := MergeAndReturnNonOverlappingIntervals(newSet)
+	return SubstractBlockedIntervals(n, o), SubstractBlockedIntervals(o, n)
+}
+
+// Patch is the stored form of a Diff, applying it to the old set gives back the new set
+type Patch struct {
+	Added   []Interval
+	Removed []Interval
+}
+
+func NewPatch(oldSet []Interval, newSet []Interval) Patch {
+	added, removed := Diff(oldSet, newSet)
+	return Patch{Added: added, Removed: removed}
+}
+
+// Apply returns the ordered disjoint intervals of set with the patch applied
+func (p Patch) Apply(set []Interval) []Interval {
+	r := SubstractBlockedIntervals(set, p.Removed)
+	return MergeAndReturnNonOverlappingIntervals(append(r, p.Added...))
+}
+
+func (p Patch) IsEmpty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0
+}
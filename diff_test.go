@@ -0,0 +1,50 @@
+package time_intervals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	// old:  AAAAA   BBB    CCC
+	// new:    DDDDDDD      EE   FFF
+	// added:       +++          +++
+	// removed: ---          -
+
+	oldSet := []Interval{testInterval(0, 5), testInterval(8, 11), testInterval(15, 18)}
+	newSet := []Interval{testInterval(2, 9), testInterval(8, 11), testInterval(15, 17), testInterval(20, 23)}
+
+	added, removed := Diff(oldSet, newSet)
+	assert.Equal(t, 2, len(added), "added: %v", added)
+	assert.Equal(t, "", intervalsDiff(testInterval(5, 8), added[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(20, 23), added[1]))
+
+	assert.Equal(t, 2, len(removed), "removed: %v", removed)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 2), removed[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(17, 18), removed[1]))
+}
+
+func Test_Diff_IgnoresOverlapsInsideOneSide(t *testing.T) {
+	// old:  AAA BBB
+	// new:  CCCCCCC   (same time, overlapping pieces)
+	//        DDD
+
+	oldSet := []Interval{testInterval(0, 3), testInterval(3, 6)}
+	newSet := []Interval{testInterval(0, 6), testInterval(1, 4)}
+
+	p := NewPatch(oldSet, newSet)
+	assert.True(t, p.IsEmpty(), "patch: %+v", p)
+}
+
+func Test_Patch_Apply(t *testing.T) {
+	oldSet := []Interval{testInterval(0, 5), testInterval(8, 11), testInterval(15, 18)}
+	newSet := []Interval{testInterval(2, 9), testInterval(8, 11), testInterval(15, 17), testInterval(20, 23)}
+
+	p := NewPatch(oldSet, newSet)
+	assertSameIntervals(t, MergeAndReturnNonOverlappingIntervals(newSet), p.Apply(oldSet))
+
+	// replaying the reverse patch goes back to the old version
+	back := NewPatch(newSet, oldSet)
+	assertSameIntervals(t, MergeAndReturnNonOverlappingIntervals(oldSet), back.Apply(p.Apply(oldSet)))
+}
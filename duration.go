@@ -0,0 +1,71 @@
+package time_intervals
+
+import "time"
+
+// TotalDuration returns the time covered by ordered disjoint intervals, such as the result of SubstractBlockedIntervals.
+// Intervals split at midnight by IntervalsByDay are counted without the millisecond missing at the end of each day.
+func TotalDuration(orderedDisjointIntervals []Interval) time.Duration {
+	total := time.Duration(0)
+	for _, i := range joinDaySplits(orderedDisjointIntervals) {
+		total += i.End.Sub(i.Start)
+	}
+	return total
+}
+
+// TimeAtOffset returns the time at which d of the covered time has passed, e.g. the end of the 10th free hour.
+// It returns false if the intervals cover less than d.
+func TimeAtOffset(orderedDisjointIntervals []Interval, d time.Duration) (time.Time, bool) {
+	for _, i := range joinDaySplits(orderedDisjointIntervals) {
+		l := i.End.Sub(i.Start)
+		if d <= l {
+			return i.Start.Add(d), true
+		}
+		d -= l
+	}
+	return time.Time{}, false
+}
+
+// TakeDuration returns the first d of the covered time, the last interval is cut if needed.
+// Intervals split at midnight by IntervalsByDay are joined back, so the pieces end at midnight in the result.
+func TakeDuration(orderedDisjointIntervals []Interval, d time.Duration) []Interval {
+	r := []Interval{}
+	for _, i := range joinDaySplits(orderedDisjointIntervals) {
+		if d <= 0 {
+			break
+		}
+		if l := i.End.Sub(i.Start); l > d {
+			i.End = i.Start.Add(d)
+		}
+		d -= i.End.Sub(i.Start)
+		r = append(r, i)
+	}
+	return r
+}
+
+// TakeDurationAfter is TakeDuration of the covered time after t, e.g. the first 3 free hours after now
+func TakeDurationAfter(orderedDisjointIntervals []Interval, t time.Time, d time.Duration) []Interval {
+	after := []Interval{}
+	for _, i := range joinDaySplits(orderedDisjointIntervals) {
+		if !i.End.After(t) {
+			continue
+		}
+		if i.Start.Before(t) {
+			i.Start = t
+		}
+		after = append(after, i)
+	}
+	return TakeDuration(after, d)
+}
+
+// gives back the millisecond IntervalsByDay leaves out at the end of a day, when the next interval continues at midnight
+func joinDaySplits(orderedDisjointIntervals []Interval) []Interval {
+	r := make([]Interval, len(orderedDisjointIntervals))
+	copy(r, orderedDisjointIntervals)
+	for k := 0; k+1 < len(r); k++ {
+		next := r[k+1].Start
+		if next.Equal(NormalizeDate(next)) && r[k].End.Add(time.Millisecond).Equal(next) {
+			r[k].End = next
+		}
+	}
+	return r
+}
@@ -0,0 +1,59 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DurationQueries(t *testing.T) {
+	// free:  AAAA   BB    CCCCCC
+
+	free := []Interval{testInterval(0, 4), testInterval(7, 9), testInterval(13, 19)}
+	assert.Equal(t, 12*time.Minute, TotalDuration(free))
+
+	at, ok := TimeAtOffset(free, 5*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, testInterval(8, 0).Start, at)
+
+	// the end of the 6th minute is the end of B, not the start of C
+	at, ok = TimeAtOffset(free, 6*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, testInterval(9, 0).Start, at)
+
+	_, ok = TimeAtOffset(free, 13*time.Minute)
+	assert.False(t, ok)
+
+	r := TakeDuration(free, 7*time.Minute)
+	assert.Equal(t, 3, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(13, 14), r[2]))
+	assert.Equal(t, 7*time.Minute, TotalDuration(r))
+
+	r = TakeDurationAfter(free, testInterval(8, 0).Start, 3*time.Minute)
+	assert.Equal(t, 2, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(8, 9), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(13, 15), r[1]))
+}
+
+func Test_DurationQueries_DaySplits(t *testing.T) {
+	// day0     day1	day2
+	// |		|		|
+	//       AAAAAAAAAAAAAA
+
+	A := testDHInterval(0, 12, 2, 6)
+	byDay := IntervalsByDay([]Interval{A})
+	split := append(append(byDay[baseTime], byDay[baseTime.AddDate(0, 0, 1)]...), byDay[baseTime.AddDate(0, 0, 2)]...)
+	assert.Equal(t, 3, len(split))
+
+	assert.Equal(t, 42*time.Hour, TotalDuration(split))
+
+	at, ok := TimeAtOffset(split, 12*time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, baseTime.AddDate(0, 0, 1), at)
+
+	r := TakeDuration(split, 13*time.Hour)
+	assert.Equal(t, 2, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 12, 1, 0), r[0]))
+	assert.Equal(t, "", intervalsDiff(testDHInterval(1, 0, 1, 1), r[1]))
+}
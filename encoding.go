@@ -0,0 +1,238 @@
+package time_intervals
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+const intervalSetVersion = 1
+
+// the range of time.Time.UnixNano
+var minUnixNanoTime = time.Unix(0, math.MinInt64)
+var maxUnixNanoTime = time.Unix(0, math.MaxInt64)
+
+var ErrInvalidEncoding = errors.New("Invalid interval set encoding")
+
+// IntervalSet is an ordered disjoint list of intervals, such as the result of SubstractBlockedIntervals, with a
+// compact binary encoding for caches: a version byte, the location, the number of intervals, the first start as Unix
+// nanoseconds and then for each interval the gap since the previous end and its length, all as varints.
+// Touching intervals are allowed. The times are decoded in the location of the first start. It is stored by name when
+// loading that name gives the same offsets, otherwise (time.FixedZone, offsets parsed from RFC 3339) as its name and
+// fixed offset. A location that is neither is rejected. time.Local is stored by its IANA name when it can be found,
+// never as "Local", so a cache shared between hosts keeps the offsets.
+type IntervalSet []Interval
+
+const namedLocation = 0
+const fixedLocation = 1
+
+func (s IntervalSet) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+binary.MaxVarintLen64*(3+2*len(s))+16)
+	b = append(b, intervalSetVersion)
+	b, err := appendLocation(b, s)
+	if err != nil {
+		return nil, err
+	}
+	b = binary.AppendUvarint(b, uint64(len(s)))
+
+	for k, i := range s {
+		if i.Start.Before(minUnixNanoTime) || i.End.After(maxUnixNanoTime) {
+			return nil, errors.New("Interval set is outside of the range of Unix nanoseconds")
+		}
+		if i.End.Before(i.Start) || (k > 0 && i.Start.Before(s[k-1].End)) {
+			return nil, errors.New("Interval set must be ordered and disjoint")
+		}
+		if k == 0 {
+			b = binary.AppendVarint(b, i.Start.UnixNano())
+		} else {
+			b = binary.AppendUvarint(b, uint64(i.Start.UnixNano()-s[k-1].End.UnixNano()))
+		}
+		b = binary.AppendUvarint(b, uint64(i.End.UnixNano()-i.Start.UnixNano()))
+	}
+	return b, nil
+}
+
+func (s *IntervalSet) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != intervalSetVersion {
+		return errors.New("Unknown interval set encoding version")
+	}
+	data = data[1:]
+
+	loc, data, err := readLocation(data)
+	if err != nil {
+		return err
+	}
+
+	count, n := binary.Uvarint(data)
+	// every interval takes at least two bytes, this also keeps a corrupted count from allocating too much
+	if n <= 0 || count > uint64(len(data)-n)/2 {
+		return ErrInvalidEncoding
+	}
+	data = data[n:]
+
+	r := make(IntervalSet, 0, count)
+	var end int64
+	for k := uint64(0); k < count; k++ {
+		var start int64
+		if k == 0 {
+			if start, n = binary.Varint(data); n <= 0 {
+				return ErrInvalidEncoding
+			}
+		} else {
+			gap, m := binary.Uvarint(data)
+			if n = m; n <= 0 || gap > headroom(end) {
+				return ErrInvalidEncoding
+			}
+			start = end + int64(gap)
+		}
+		data = data[n:]
+
+		length, n := binary.Uvarint(data)
+		if n <= 0 || length > headroom(start) {
+			return ErrInvalidEncoding
+		}
+		data = data[n:]
+		end = start + int64(length)
+
+		r = append(r, Interval{Start: time.Unix(0, start).In(loc), End: time.Unix(0, end).In(loc)})
+	}
+	if len(data) > 0 {
+		return ErrInvalidEncoding
+	}
+	*s = r
+	return nil
+}
+
+// how much can be added to v before it overflows an int64, also for negative v
+func headroom(v int64) uint64 {
+	return uint64(math.MaxInt64) - uint64(v)
+}
+
+// appends the location of the first start: by name when loading it by name gives every time of the set the same
+// offset, otherwise as a fixed zone when every time has the same offset in it
+func appendLocation(b []byte, s IntervalSet) ([]byte, error) {
+	loc := time.UTC
+	if len(s) > 0 {
+		loc = s[0].Start.Location()
+	}
+	name := loc.String()
+	if loc == time.Local {
+		// "Local" would be decoded as the local location of the decoding host
+		name = localZoneName()
+	}
+	if named, err := loadLocation(name); err == nil && sameOffsets(s, loc, named) {
+		b = append(b, namedLocation)
+		b = binary.AppendUvarint(b, uint64(len(name)))
+		return append(b, name...), nil
+	}
+
+	abbreviation, offset := s[0].Start.Zone()
+	if loc == time.Local {
+		name = abbreviation
+	}
+	if !sameOffsets(s, loc, time.FixedZone(name, offset)) {
+		return nil, errors.New(fmt.Sprintf("Location %q can not be loaded by name and has no fixed offset", name))
+	}
+	b = append(b, fixedLocation)
+	b = binary.AppendUvarint(b, uint64(len(name)))
+	b = append(b, name...)
+	return binary.AppendVarint(b, int64(offset)), nil
+}
+
+func sameOffsets(s IntervalSet, a, b *time.Location) bool {
+	for _, i := range s {
+		if !sameOffset(i.Start, a, b) || !sameOffset(i.End, a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameOffset(t time.Time, a, b *time.Location) bool {
+	_, x := t.In(a).Zone()
+	_, y := t.In(b).Zone()
+	return x == y
+}
+
+// reads the location written by appendLocation and returns the rest of the data
+func readLocation(data []byte) (*time.Location, []byte, error) {
+	if len(data) == 0 || data[0] > fixedLocation {
+		return nil, nil, ErrInvalidEncoding
+	}
+	kind := data[0]
+	data = data[1:]
+
+	nameLength, n := binary.Uvarint(data)
+	if n <= 0 || nameLength > uint64(len(data)-n) {
+		return nil, nil, ErrInvalidEncoding
+	}
+	name := string(data[n : n+int(nameLength)])
+	data = data[n+int(nameLength):]
+
+	if kind == namedLocation {
+		loc, err := loadLocation(name)
+		return loc, data, err
+	}
+	offset, n := binary.Varint(data)
+	if n <= 0 || offset < -24*60*60 || offset > 24*60*60 {
+		return nil, nil, ErrInvalidEncoding
+	}
+	return time.FixedZone(name, int(offset)), data[n:], nil
+}
+
+// loaded locations by name, time.LoadLocation reads the zone database on every call. Failures are not kept, the
+// names come from the decoded data and could fill the cache.
+var loadedLocations sync.Map
+
+func loadLocation(name string) (*time.Location, error) {
+	switch name {
+	case "":
+		// time.LoadLocation returns UTC for it, but it is the name of fixed zones, e.g. from RFC 3339 offsets
+		return nil, errors.New("Location without a name can not be loaded")
+	case "UTC":
+		return time.UTC, nil
+	case "Local":
+		return nil, errors.New("The local location of another host can not be loaded")
+	}
+	if loc, ok := loadedLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	loadedLocations.Store(name, loc)
+	return loc, nil
+}
+
+// the IANA name of time.Local, from $TZ or the /etc/localtime link like the time package finds it, "" if unknown
+func localZoneName() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		tz = strings.TrimPrefix(tz, ":")
+		if tz == "" {
+			return "UTC"
+		}
+		if !filepath.IsAbs(tz) {
+			return tz
+		}
+		return zoneNameFromPath(tz)
+	}
+	if p, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		return zoneNameFromPath(p)
+	}
+	return ""
+}
+
+func zoneNameFromPath(p string) string {
+	if i := strings.LastIndex(p, "zoneinfo/"); i >= 0 {
+		return p[i+len("zoneinfo/"):]
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package time_intervals
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IntervalSet_Binary(t *testing.T) {
+	s := IntervalSet(SubstractBlockedIntervals(
+		[]Interval{testInterval(0, 600), testInterval(700, 900)},
+		[]Interval{testInterval(30, 60), testInterval(120, 125)},
+	))
+	s = append(s, Interval{Start: testInterval(900, 0).Start, End: testInterval(900, 0).Start.Add(1500 * time.Millisecond)})
+
+	b, err := s.MarshalBinary()
+	assert.NoError(t, err)
+	// less than the 16 bytes per interval of two raw int64 timestamps
+	assert.True(t, len(b) < 16*len(s), "encoding is %d bytes long", len(b))
+
+	decoded := IntervalSet{}
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assertSameIntervals(t, s, decoded)
+}
+
+func Test_IntervalSet_Binary_Location(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	s := IntervalSet{{Start: time.Date(2018, 4, 7, 9, 0, 0, 0, ny), End: time.Date(2018, 4, 7, 17, 0, 0, 0, ny)}}
+
+	b, err := s.MarshalBinary()
+	assert.NoError(t, err)
+	decoded := IntervalSet{}
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, "America/New_York", decoded[0].Start.Location().String())
+	assert.Equal(t, 9, decoded[0].Start.Hour())
+}
+
+func Test_IntervalSet_Binary_FixedZone(t *testing.T) {
+	plusTwo := time.FixedZone("UTC+2", 7200)
+	parsed, err := time.Parse(time.RFC3339, "2018-04-07T09:00:00+03:00")
+	assert.NoError(t, err)
+
+	for _, start := range []time.Time{time.Date(2018, 4, 7, 9, 0, 0, 0, plusTwo), parsed} {
+		s := IntervalSet{{Start: start, End: start.Add(8 * time.Hour)}}
+		b, err := s.MarshalBinary()
+		if !assert.NoError(t, err) {
+			continue
+		}
+		decoded := IntervalSet{}
+		if !assert.NoError(t, decoded.UnmarshalBinary(b)) {
+			continue
+		}
+		assert.True(t, start.Equal(decoded[0].Start))
+		assert.Equal(t, start.Format(time.RFC3339), decoded[0].Start.Format(time.RFC3339), "the wall clock is kept")
+		assert.Equal(t, start.Location().String(), decoded[0].Start.Location().String())
+		assert.Equal(t, 9, decoded[0].Start.Hour())
+	}
+}
+
+func Test_IntervalSet_Binary_NoFixedOffset(t *testing.T) {
+	data, err := os.ReadFile("/usr/share/zoneinfo/America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// a zone with daylight saving time that can not be loaded by its name
+	office, err := time.LoadLocationFromTZData("Office", data)
+	assert.NoError(t, err)
+
+	winter := IntervalSet{{Start: time.Date(2018, 1, 7, 9, 0, 0, 0, office), End: time.Date(2018, 1, 7, 10, 0, 0, 0, office)}}
+	b, err := winter.MarshalBinary()
+	assert.NoError(t, err, "a single offset is stored as a fixed zone")
+	decoded := IntervalSet{}
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, 9, decoded[0].Start.Hour())
+
+	year := append(winter, Interval{Start: time.Date(2018, 7, 7, 9, 0, 0, 0, office), End: time.Date(2018, 7, 7, 10, 0, 0, 0, office)})
+	_, err = year.MarshalBinary()
+	assert.Error(t, err)
+}
+
+func Test_IntervalSet_Binary_Local(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	defer func(l time.Location) { *time.Local = l }(*time.Local)
+
+	// the local location is stored by its name, the decoding host may be in another zone
+	t.Setenv("TZ", "Asia/Kolkata")
+	*time.Local = *kolkata
+	s := IntervalSet{{Start: time.Date(2018, 4, 7, 9, 0, 0, 0, time.Local), End: time.Date(2018, 4, 7, 17, 0, 0, 0, time.Local)}}
+	b, err := s.MarshalBinary()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "Local")
+
+	*time.Local = *berlin
+	decoded := IntervalSet{}
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, "Asia/Kolkata", decoded[0].Start.Location().String())
+	assert.Equal(t, 9, decoded[0].Start.Hour())
+
+	// without a known name it is a fixed zone, which does not work over a daylight saving change
+	t.Setenv("TZ", "/nowhere/berlin")
+	s = IntervalSet{{Start: time.Date(2018, 1, 7, 9, 0, 0, 0, time.Local), End: time.Date(2018, 1, 7, 17, 0, 0, 0, time.Local)}}
+	b, err = s.MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, "CET", decoded[0].Start.Location().String())
+	assert.Equal(t, 9, decoded[0].Start.Hour())
+
+	s = append(s, Interval{Start: time.Date(2018, 7, 7, 9, 0, 0, 0, time.Local), End: time.Date(2018, 7, 7, 17, 0, 0, 0, time.Local)})
+	_, err = s.MarshalBinary()
+	assert.Error(t, err)
+
+	assert.Error(t, decoded.UnmarshalBinary([]byte{intervalSetVersion, namedLocation, 5, 'L', 'o', 'c', 'a', 'l', 0}))
+}
+
+func Test_IntervalSet_Binary_Errors(t *testing.T) {
+	_, err := IntervalSet{testInterval(5, 10), testInterval(0, 3)}.MarshalBinary()
+	assert.Error(t, err, "not ordered")
+
+	_, err = IntervalSet{testInterval(0, 10), testInterval(5, 15)}.MarshalBinary()
+	assert.Error(t, err, "overlapping")
+
+	b, err := IntervalSet{testInterval(0, 10), testInterval(10, 15)}.MarshalBinary()
+	assert.NoError(t, err, "touching intervals are fine")
+
+	s := IntervalSet{}
+	assert.Error(t, s.UnmarshalBinary(b[:len(b)-1]), "truncated")
+	assert.Error(t, s.UnmarshalBinary(append(b, 0)), "trailing bytes")
+	assert.Error(t, s.UnmarshalBinary(append([]byte{2}, b[1:]...)), "unknown version")
+	assert.Error(t, s.UnmarshalBinary(nil))
+
+	// a gap that would wrap around to before the previous end
+	b = []byte{intervalSetVersion, namedLocation, 3, 'U', 'T', 'C', 2, 19, 0}
+	b = binary.AppendUvarint(b, math.MaxUint64)
+	b = append(b, 0)
+	assert.Equal(t, ErrInvalidEncoding, s.UnmarshalBinary(b))
+}
+
+func FuzzIntervalSet_UnmarshalBinary(f *testing.F) {
+	for _, s := range []IntervalSet{{}, {testInterval(0, 10)}, {testInterval(0, 10), testInterval(10, 15), testInterval(20, 21)}} {
+		b, _ := s.MarshalBinary()
+		f.Add(b)
+	}
+	f.Add([]byte{1, 0, 3, 'U', 'T', 'C', 0xff, 0xff, 0xff, 0xff, 0x0f})
+	f.Add([]byte{1, 1, 3, 'C', 'E', 'T', 0x80, 0x38, 1, 0, 2})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := IntervalSet{}
+		if s.UnmarshalBinary(data) != nil {
+			return
+		}
+		// whatever decodes must be a valid set that survives a round trip
+		b, err := s.MarshalBinary()
+		if !assert.NoError(t, err) {
+			return
+		}
+		again := IntervalSet{}
+		if !assert.NoError(t, again.UnmarshalBinary(b)) || !assert.Equal(t, len(s), len(again)) {
+			return
+		}
+		// loaded locations are not the same pointer, so compare instants, names and offsets
+		for k := range s {
+			assert.True(t, s[k].Start.Equal(again[k].Start) && s[k].End.Equal(again[k].End), "%v <> %v", s[k], again[k])
+			assert.Equal(t, s[k].Start.Location().String(), again[k].Start.Location().String())
+			assert.Equal(t, s[k].Start.Format(time.RFC3339Nano), again[k].Start.Format(time.RFC3339Nano))
+		}
+		b2, err := again.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, b, b2)
+	})
+}
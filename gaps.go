@@ -0,0 +1,65 @@
+package time_intervals
+
+import (
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+type GapOptions struct {
+	MinLength time.Duration  // shorter gaps are dropped
+	Location  *time.Location // the daily bounds of DailyGaps are wall clock times in this location, defaults to UTC
+}
+
+// Gaps returns the ordered free intervals inside within that are not covered by any busy interval.
+// The busy intervals do not need to be sorted or disjoint.
+func Gaps(busy []Interval, within Interval, opts GapOptions) []Interval {
+	r := []Interval{}
+	for _, g := range SubstractBlockedIntervals([]Interval{within}, busy) {
+		if g.End.Sub(g.Start) < opts.MinLength {
+			continue
+		}
+		if opts.Location != nil {
+			g = Interval{Start: g.Start.In(opts.Location), End: g.End.In(opts.Location)}
+		}
+		r = append(r, g)
+	}
+	return r
+}
+
+// DailyGaps returns the Gaps between from and to (wall clock offsets since midnight, e.g. 9*time.Hour and 18*time.Hour)
+// for each day between startDay and endDay inclusive. The days and the bounds are taken in opts.Location, so a 09:00
+// bound stays at 09:00 across daylight saving changes. DayIntervals.Date is the calendar date, normalized like NormalizeDate.
+func DailyGaps(busy []Interval, startDay, endDay time.Time, from, to time.Duration, opts GapOptions) ([]DayIntervals, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	startDay, endDay = startDay.In(loc), endDay.In(loc)
+	if startDay.Sub(endDay).Seconds() > 0 {
+		return []DayIntervals{}, errors.New("Start day must be before the end")
+	}
+	if endDay.Sub(startDay).Hours() > 24*365 {
+		return []DayIntervals{}, errors.New("Can not request more than 365 days")
+	}
+	if from > to {
+		return []DayIntervals{}, errors.New("Daily bounds must start before they end")
+	}
+
+	merged := MergeAndReturnNonOverlappingIntervals(busy)
+	result := []DayIntervals{}
+	d := 0
+	for c := time.Date(startDay.Year(), startDay.Month(), startDay.Day(), 0, 0, 0, 0, loc); !c.After(endDay); c = c.AddDate(0, 0, 1) {
+		within := Interval{
+			Start: time.Date(c.Year(), c.Month(), c.Day(), 0, 0, 0, int(from), loc),
+			End:   time.Date(c.Year(), c.Month(), c.Day(), 0, 0, 0, int(to), loc),
+		}
+		result = append(result, DayIntervals{
+			Date:                     NormalizeDate(c),
+			CountSinceFirst:          d,
+			OrderedDisjunctIntervals: Gaps(merged, within, opts),
+		})
+		d++
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Gaps(t *testing.T) {
+	// within:   WWWWWWWWWWWWWWWWWW
+	// busy:   AAAA   BB  CCC D   EEEE
+	// gaps:       GGG  GG   G GGG
+
+	busy := []Interval{testInterval(0, 4), testInterval(7, 9), testInterval(11, 14), testInterval(15, 16), testInterval(19, 23)}
+	within := testInterval(2, 20)
+
+	g := Gaps(busy, within, GapOptions{})
+	assert.Equal(t, 4, len(g), "result: %v", g)
+	assert.Equal(t, "", intervalsDiff(testInterval(4, 7), g[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(9, 11), g[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(14, 15), g[2]))
+	assert.Equal(t, "", intervalsDiff(testInterval(16, 19), g[3]))
+
+	g = Gaps(busy, within, GapOptions{MinLength: 3 * time.Minute})
+	assert.Equal(t, 2, len(g), "result: %v", g)
+	assert.Equal(t, "", intervalsDiff(testInterval(4, 7), g[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(16, 19), g[1]))
+}
+
+func Test_DailyGaps_KeepsWallClockAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// DST starts on 2018-03-11 in New York
+	meeting := Interval{Start: time.Date(2018, 3, 11, 10, 0, 0, 0, ny), End: time.Date(2018, 3, 11, 11, 0, 0, 0, ny)}
+	days, err := DailyGaps([]Interval{meeting}, time.Date(2018, 3, 10, 0, 0, 0, 0, ny), time.Date(2018, 3, 12, 0, 0, 0, 0, ny), 9*time.Hour, 18*time.Hour, GapOptions{Location: ny})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(days))
+
+	for _, d := range days {
+		first := d.OrderedDisjunctIntervals[0]
+		last := d.OrderedDisjunctIntervals[len(d.OrderedDisjunctIntervals)-1]
+		assert.Equal(t, 9, first.Start.Hour(), "day %v", d.Date)
+		assert.Equal(t, 18, last.End.Hour(), "day %v", d.Date)
+	}
+
+	assert.Equal(t, time.Date(2018, 3, 11, 0, 0, 0, 0, time.UTC), days[1].Date)
+	assert.Equal(t, 2, len(days[1].OrderedDisjunctIntervals))
+	assert.Equal(t, 10, days[1].OrderedDisjunctIntervals[0].End.Hour())
+	assert.Equal(t, 11, days[1].OrderedDisjunctIntervals[1].Start.Hour())
+}
+
+func Test_DailyGaps_InvalidRange(t *testing.T) {
+	_, err := DailyGaps([]Interval{}, baseTime.AddDate(0, 0, 1), baseTime, 9*time.Hour, 18*time.Hour, GapOptions{})
+	assert.Error(t, err)
+
+	_, err = DailyGaps([]Interval{}, baseTime, baseTime, 18*time.Hour, 9*time.Hour, GapOptions{})
+	assert.Error(t, err)
+}
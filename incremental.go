@@ -0,0 +1,235 @@
+package time_intervals
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+var ErrNotScheduled = errors.New("Interval was not added to the schedule")
+
+// a point where the coverage changes, the counts hold from Time until the next point
+type coveragePoint struct {
+	Time      time.Time
+	available int
+	blocked   int
+}
+
+// IncrementalSchedule keeps the result of SubstractBlockedIntervals up to date while single available or
+// blocked intervals are added and removed, without recomputing everything from scratch.
+//
+// The coverage is stored as a step function in a skip list ordered by time. An update finds its position in
+// expected O(log n) and then only touches the k points inside the updated interval, so it costs O(log n + k).
+// Only intervals that were added before can be removed, removing a part of one returns ErrNotScheduled.
+// Zero length intervals have no effect on the schedule.
+type IncrementalSchedule struct {
+	points pointList
+	added  map[scheduledInterval]int
+}
+
+// identifies an added interval by its instants, independent of the location and the monotonic clock
+type scheduledInterval struct {
+	blocked    bool
+	start, end time.Time
+}
+
+func NewIncrementalSchedule(available []Interval, blocked []Interval) *IncrementalSchedule {
+	s := &IncrementalSchedule{added: map[scheduledInterval]int{}}
+
+	deltas := []coveragePoint{}
+	for _, a := range available {
+		if a.Start.Before(a.End) {
+			deltas = append(deltas, coveragePoint{a.Start, 1, 0}, coveragePoint{a.End, -1, 0})
+			s.added[scheduledKey(a, false)]++
+		}
+	}
+	for _, b := range blocked {
+		if b.Start.Before(b.End) {
+			deltas = append(deltas, coveragePoint{b.Start, 0, 1}, coveragePoint{b.End, 0, -1})
+			s.added[scheduledKey(b, true)]++
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Time.Before(deltas[j].Time) })
+
+	// turn the deltas into running counts, keeping a single point for each time where something changes
+	last := s.points.init()
+	current := coveragePoint{}
+	for i, d := range deltas {
+		current.available += d.available
+		current.blocked += d.blocked
+		if i+1 < len(deltas) && deltas[i+1].Time.Equal(d.Time) {
+			continue
+		}
+		current.Time = d.Time
+		if prev := last[0]; current.available != prev.available || current.blocked != prev.blocked {
+			s.points.insert(&last, current)
+		}
+	}
+	return s
+}
+
+func (s *IncrementalSchedule) AddAvailable(i Interval) error {
+	return s.update(i, false, 1)
+}
+
+func (s *IncrementalSchedule) RemoveAvailable(i Interval) error {
+	return s.update(i, false, -1)
+}
+
+func (s *IncrementalSchedule) AddBlocked(i Interval) error {
+	return s.update(i, true, 1)
+}
+
+func (s *IncrementalSchedule) RemoveBlocked(i Interval) error {
+	return s.update(i, true, -1)
+}
+
+// Free returns the current available minus blocked intervals, ordered and disjoint. It walks the whole step
+// function, so it is O(n) like copying the result would be.
+// Unlike SubstractBlockedIntervals, which splits a free interval in two touching pieces at an empty blocked interval,
+// the result is always merged: it equals MergeAndReturnNonOverlappingIntervals of the batch result.
+func (s *IncrementalSchedule) Free() []Interval {
+	runs := []Interval{}
+	for p := s.points.head.next[0]; p != nil && p.next[0] != nil; p = p.next[0] {
+		if p.available == 0 || p.blocked > 0 {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].End.Equal(p.Time) {
+			runs[n-1].End = p.next[0].Time
+		} else {
+			runs = append(runs, Interval{Start: p.Time, End: p.next[0].Time})
+		}
+	}
+	return runs
+}
+
+func (s *IncrementalSchedule) update(i Interval, blocked bool, delta int) error {
+	if i.End.Before(i.Start) {
+		return ErrInvalidInterval
+	}
+	if !i.Start.Before(i.End) {
+		return nil
+	}
+	key := scheduledKey(i, blocked)
+	if delta < 0 && s.added[key] == 0 {
+		return ErrNotScheduled
+	}
+	if s.added[key] += delta; s.added[key] == 0 {
+		delete(s.added, key)
+	}
+
+	end := s.ensurePoint(i.End)
+	start := s.ensurePoint(i.Start)
+	for p := start; p != end; p = p.next[0] {
+		if blocked {
+			p.blocked += delta
+		} else {
+			p.available += delta
+		}
+	}
+	s.removeIfRedundant(end)
+	s.removeIfRedundant(start)
+	return nil
+}
+
+func scheduledKey(i Interval, blocked bool) scheduledInterval {
+	return scheduledInterval{blocked: blocked, start: i.Start.Round(0).UTC(), end: i.End.Round(0).UTC()}
+}
+
+// returns the point at t, inserting one with the counts of the previous point if needed
+func (s *IncrementalSchedule) ensurePoint(t time.Time) *pointNode {
+	var update [maxPointLevel]*pointNode
+	if p := s.points.seek(t, &update); p != nil && p.Time.Equal(t) {
+		return p
+	}
+	prev := update[0]
+	return s.points.insert(&update, coveragePoint{Time: t, available: prev.available, blocked: prev.blocked})
+}
+
+// removes the point if it has the same counts as the previous one
+func (s *IncrementalSchedule) removeIfRedundant(p *pointNode) {
+	if p.available == p.prev.available && p.blocked == p.prev.blocked {
+		s.points.remove(p)
+	}
+}
+
+const maxPointLevel = 32
+
+type pointNode struct {
+	coveragePoint
+	prev *pointNode
+	next []*pointNode
+}
+
+// a skip list of points ordered by time, the head has zero counts like the time before the first point
+type pointList struct {
+	head  pointNode
+	level int
+	rnd   uint64
+}
+
+// prepares an empty list and returns the predecessors for appending points in order
+func (l *pointList) init() (last [maxPointLevel]*pointNode) {
+	l.head.next = make([]*pointNode, maxPointLevel)
+	l.level = 1
+	l.rnd = 0x9e3779b97f4a7c15
+	for i := range last {
+		last[i] = &l.head
+	}
+	return last
+}
+
+// returns the first point at or after t and fills update with the last point before t on every level
+func (l *pointList) seek(t time.Time, update *[maxPointLevel]*pointNode) *pointNode {
+	p := &l.head
+	for i := maxPointLevel - 1; i >= 0; i-- {
+		for i < l.level && p.next[i] != nil && p.next[i].Time.Before(t) {
+			p = p.next[i]
+		}
+		update[i] = p
+	}
+	return p.next[0]
+}
+
+// inserts the point after the predecessors in update and moves them to the new point
+func (l *pointList) insert(update *[maxPointLevel]*pointNode, c coveragePoint) *pointNode {
+	level := l.randomLevel()
+	if level > l.level {
+		l.level = level
+	}
+	p := &pointNode{coveragePoint: c, prev: update[0], next: make([]*pointNode, level)}
+	for i := 0; i < level; i++ {
+		p.next[i] = update[i].next[i]
+		update[i].next[i] = p
+		update[i] = p
+	}
+	if p.next[0] != nil {
+		p.next[0].prev = p
+	}
+	return p
+}
+
+func (l *pointList) remove(p *pointNode) {
+	var update [maxPointLevel]*pointNode
+	l.seek(p.Time, &update)
+	for i := range p.next {
+		update[i].next[i] = p.next[i]
+	}
+	if p.next[0] != nil {
+		p.next[0].prev = p.prev
+	}
+}
+
+// each level is four times sparser than the one below
+func (l *pointList) randomLevel() int {
+	// xorshift64, deterministic so the schedules behave the same on every run
+	l.rnd ^= l.rnd << 13
+	l.rnd ^= l.rnd >> 7
+	l.rnd ^= l.rnd << 17
+	level := 1
+	for x := l.rnd; x&3 == 0 && level < maxPointLevel; x >>= 2 {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,122 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IncrementalSchedule_AddRemove(t *testing.T) {
+	// available:  AAAAAAAAAA    CCCCC
+	// blocked:       BBB          DDDDD
+	// free:       111   222    3
+
+	s := NewIncrementalSchedule([]Interval{testInterval(0, 10)}, []Interval{testInterval(3, 6)})
+	assert.NoError(t, s.AddAvailable(testInterval(14, 19)))
+	assert.NoError(t, s.AddBlocked(testInterval(15, 20)))
+
+	free := s.Free()
+	assert.Equal(t, 3, len(free), "result: %v", free)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 3), free[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(6, 10), free[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(14, 15), free[2]))
+
+	// removing B joins the first two free intervals, adding the gap joins A and C
+	assert.NoError(t, s.RemoveBlocked(testInterval(3, 6)))
+	assert.NoError(t, s.AddAvailable(testInterval(10, 14)))
+	free = s.Free()
+	assert.Equal(t, 1, len(free), "result: %v", free)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 15), free[0]))
+
+	assert.Equal(t, ErrNotScheduled, s.RemoveBlocked(testInterval(3, 6)))
+	assert.Equal(t, ErrNotScheduled, s.RemoveAvailable(testInterval(12, 20)))
+	assert.Equal(t, 1, len(s.Free()))
+}
+
+func Test_IncrementalSchedule_RemovePartOfInterval(t *testing.T) {
+	// available:  AAAAAAAAAAAAAAAAAAAA
+	// blocked:    BBBBBBBBBB
+	// remove:       XXX        (only a part of B, must not open a hole)
+
+	s := NewIncrementalSchedule([]Interval{testInterval(0, 20)}, []Interval{testInterval(0, 10)})
+	assert.Equal(t, ErrNotScheduled, s.RemoveBlocked(testInterval(2, 5)))
+	assert.Equal(t, ErrNotScheduled, s.RemoveAvailable(testInterval(0, 10)), "blocked and available are tracked apart")
+
+	free := s.Free()
+	assert.Equal(t, 1, len(free), "result: %v", free)
+	assert.Equal(t, "", intervalsDiff(testInterval(10, 20), free[0]))
+
+	// the same instants in another location are the same interval
+	assert.NoError(t, s.RemoveBlocked(Interval{Start: testInterval(0, 10).Start.In(time.FixedZone("UTC+2", 7200)), End: testInterval(0, 10).End}))
+	assertSameIntervals(t, []Interval{testInterval(0, 20)}, s.Free())
+}
+
+func Test_IncrementalSchedule_MatchesBatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	randomInterval := func() Interval {
+		s := rnd.Intn(200)
+		if rnd.Intn(10) == 0 {
+			// empty intervals have no effect here, but split the batch result
+			return testInterval(s, s)
+		}
+		return testInterval(s, s+1+rnd.Intn(30))
+	}
+	batchFree := func(available, blocked []Interval) []Interval {
+		return MergeAndReturnNonOverlappingIntervals(SubstractBlockedIntervals(available, blocked))
+	}
+
+	for run := 0; run < 50; run++ {
+		available := []Interval{}
+		blocked := []Interval{}
+		for i := 0; i < rnd.Intn(10); i++ {
+			available = append(available, randomInterval())
+		}
+		for i := 0; i < rnd.Intn(10); i++ {
+			blocked = append(blocked, randomInterval())
+		}
+		s := NewIncrementalSchedule(available, blocked)
+		assertSameIntervals(t, batchFree(available, blocked), s.Free())
+
+		for step := 0; step < 100; step++ {
+			switch rnd.Intn(4) {
+			case 0:
+				i := randomInterval()
+				available = append(available, i)
+				assert.NoError(t, s.AddAvailable(i))
+			case 1:
+				i := randomInterval()
+				blocked = append(blocked, i)
+				assert.NoError(t, s.AddBlocked(i))
+			case 2:
+				if len(available) > 0 {
+					k := rnd.Intn(len(available))
+					assert.NoError(t, s.RemoveAvailable(available[k]))
+					available = append(available[:k], available[k+1:]...)
+				}
+			case 3:
+				if len(blocked) > 0 {
+					k := rnd.Intn(len(blocked))
+					assert.NoError(t, s.RemoveBlocked(blocked[k]))
+					blocked = append(blocked[:k], blocked[k+1:]...)
+				}
+			}
+			if !assertSameIntervals(t, batchFree(available, blocked), s.Free()) {
+				return
+			}
+		}
+	}
+}
+
+func assertSameIntervals(t *testing.T, expected, actual []Interval) bool {
+	if !assert.Equal(t, len(expected), len(actual), "expected %v, got %v", expected, actual) {
+		return false
+	}
+	for i := range expected {
+		if !assert.Equal(t, "", intervalsDiff(expected[i], actual[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -16,6 +16,16 @@ func (i *Interval) String() string {
 	return fmt.Sprintf("(%v -> %v)[%f minutes]", i.Start, i.End, i.End.Sub(i.Start).Minutes())
 }
 
+// intervals are treated as half open [Start, End), so intervals that only touch do not overlap
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// returns true if o lies completely inside i
+func (i Interval) Contains(o Interval) bool {
+	return !o.Start.Before(i.Start) && !o.End.After(i.End)
+}
+
 type IntervalType string
 
 const Available IntervalType = "available"
@@ -67,6 +77,11 @@ func (h *EndpointsHeap) Pop() interface{} {
 }
 
 func SubstractBlockedIntervals(available []Interval, blocked []Interval) []Interval {
+	if IsSortedDisjoint(available) && IsSortedDisjoint(blocked) {
+		// typical for data coming from a database ordered by start, no need for the heap
+		return SubstractSortedDisjoint(available, blocked)
+	}
+
 	h := buildEndpointsHeap(available, blocked)
 	nItems := len(*h)
 
@@ -161,19 +176,31 @@ func IntervalsByDay(a []Interval) map[time.Time][]Interval {
 	m := map[time.Time][]Interval{}
 
 	for _, i := range a {
-		if SameDay(i.Start, i.End) {
-			addIntervalToMap(m, i)
-		} else {
-			addIntervalToMap(m, Interval{Start: i.Start, End: NormalizeDate(i.Start).AddDate(0, 0, 1).Add(-1 * time.Millisecond)})
-			for cd := NormalizeDate(i.Start).AddDate(0, 0, 1); i.End.Sub(cd).Hours() >= 24; cd = cd.AddDate(0, 0, 1) {
-				addIntervalToMap(m, Interval{Start: cd, End: cd.AddDate(0, 0, 1).Add(-1 * time.Millisecond)})
-			}
-			addIntervalToMap(m, Interval{Start: NormalizeDate(i.End), End: i.End})
-		}
+		splitByDay(i, func(sameDayInterval Interval) bool {
+			addIntervalToMap(m, sameDayInterval)
+			return true
+		})
 	}
 	return m
 }
 
+// calls yield with the part of the interval inside each day it touches, a day ends one millisecond before midnight.
+// Returns false if yield asked to stop.
+func splitByDay(i Interval, yield func(Interval) bool) bool {
+	if SameDay(i.Start, i.End) {
+		return yield(i)
+	}
+	if !yield(Interval{Start: i.Start, End: NormalizeDate(i.Start).AddDate(0, 0, 1).Add(-1 * time.Millisecond)}) {
+		return false
+	}
+	for cd := NormalizeDate(i.Start).AddDate(0, 0, 1); i.End.Sub(cd).Hours() >= 24; cd = cd.AddDate(0, 0, 1) {
+		if !yield(Interval{Start: cd, End: cd.AddDate(0, 0, 1).Add(-1 * time.Millisecond)}) {
+			return false
+		}
+	}
+	return yield(Interval{Start: NormalizeDate(i.End), End: i.End})
+}
+
 func SameDay(a, b time.Time) bool {
 	ay, am, ad := a.Date()
 	by, bm, bd := b.Date()
@@ -227,9 +254,38 @@ func SplitInFixedIntervals(orderedDisjointIntervals []Interval, intervalLengthIn
 	r := []Interval{}
 	l := time.Duration(intervalLengthInMinutes)
 	for _, i := range orderedDisjointIntervals {
-		for c := i.Start; i.End.Add(-l*time.Minute).Sub(c).Seconds() > -1; c = c.Add(l*time.Minute) {
-			r = append(r, Interval{Start: c, End: c.Add(l*time.Minute)})
+		splitInFixedIntervals(i, l*time.Minute, func(slot Interval) bool {
+			r = append(r, slot)
+			return true
+		})
+	}
+	return r
+}
+
+// calls yield with each slot of length l that fits in the interval, returns false if yield asked to stop
+func splitInFixedIntervals(i Interval, l time.Duration, yield func(Interval) bool) bool {
+	for c := i.Start; i.End.Add(-l).Sub(c).Seconds() > -1; c = c.Add(l) {
+		if !yield(Interval{Start: c, End: c.Add(l)}) {
+			return false
+		}
+	}
+	return true
+}
+
+// cuts every interval of an ordered disjoint list to the window, dropping the ones that fall outside of it
+func clipIntervals(orderedDisjointIntervals []Interval, window Interval) []Interval {
+	r := []Interval{}
+	for _, i := range orderedDisjointIntervals {
+		if !i.Overlaps(window) {
+			continue
+		}
+		if i.Start.Before(window.Start) {
+			i.Start = window.Start
+		}
+		if i.End.After(window.End) {
+			i.End = window.End
 		}
+		r = append(r, i)
 	}
 	return r
 }
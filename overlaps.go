@@ -0,0 +1,139 @@
+package time_intervals
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// LabeledInterval is an interval tagged with what it belongs to, e.g. a booking id or a person
+type LabeledInterval struct {
+	Label    string
+	Interval Interval
+}
+
+// Overlap is a pair of overlapping input intervals, by index with I < J, and the time they share
+type Overlap struct {
+	I, J     int
+	Interval Interval
+}
+
+// LabeledOverlap is an Overlap between two labeled intervals
+type LabeledOverlap struct {
+	A, B     LabeledInterval
+	Interval Interval
+}
+
+// the indexes of the intervals that are open during the sweep, the first one ends first
+type activeIntervals struct {
+	idx       []int
+	intervals []Interval
+}
+
+func (h activeIntervals) Len() int { return len(h.idx) }
+func (h activeIntervals) Less(i, j int) bool {
+	return h.intervals[h.idx[i]].End.Before(h.intervals[h.idx[j]].End)
+}
+func (h activeIntervals) Swap(i, j int) { h.idx[i], h.idx[j] = h.idx[j], h.idx[i] }
+
+func (h *activeIntervals) Push(x interface{}) {
+	h.idx = append(h.idx, x.(int))
+}
+
+func (h *activeIntervals) Pop() interface{} {
+	x := h.idx[len(h.idx)-1]
+	h.idx = h.idx[0 : len(h.idx)-1]
+	return x
+}
+
+// FindOverlaps returns every pair of overlapping intervals ordered by I and then J. Unlike
+// MergeAndReturnNonOverlappingIntervals it does not hide double bookings.
+// The intervals are swept by start while a heap keeps the open ones by end, so it runs in O(n log n + k) for k
+// overlaps. Intervals that only touch do not overlap and empty intervals are ignored.
+func FindOverlaps(intervals []Interval) []Overlap {
+	r := []Overlap{}
+	active := &activeIntervals{intervals: intervals}
+	for _, i := range byStart(intervals) {
+		cur := intervals[i]
+		for active.Len() > 0 && !intervals[active.idx[0]].End.After(cur.Start) {
+			heap.Pop(active)
+		}
+		for _, j := range active.idx {
+			o := Overlap{I: j, J: i, Interval: Interval{Start: cur.Start, End: intervals[j].End}}
+			if o.I > o.J {
+				o.I, o.J = o.J, o.I
+			}
+			if cur.End.Before(o.Interval.End) {
+				o.Interval.End = cur.End
+			}
+			r = append(r, o)
+		}
+		heap.Push(active, i)
+	}
+
+	sort.Slice(r, func(a, b int) bool {
+		if r[a].I == r[b].I {
+			return r[a].J < r[b].J
+		}
+		return r[a].I < r[b].I
+	})
+	return r
+}
+
+// FindLabeledOverlaps is FindOverlaps for labeled intervals
+func FindLabeledOverlaps(intervals []LabeledInterval) []LabeledOverlap {
+	r := []LabeledOverlap{}
+	for _, o := range FindOverlaps(unlabel(intervals)) {
+		r = append(r, LabeledOverlap{A: intervals[o.I], B: intervals[o.J], Interval: o.Interval})
+	}
+	return r
+}
+
+// OverlapClusters groups the intervals that overlap each other, directly or through other intervals in the group.
+// Each cluster lists the indexes in increasing order and the clusters are ordered by time. Intervals that do not
+// overlap anything are left out.
+func OverlapClusters(intervals []Interval) [][]int {
+	r := [][]int{}
+	cluster := []int{}
+	flush := func() {
+		if len(cluster) > 1 {
+			sort.Ints(cluster)
+			r = append(r, cluster)
+		}
+		cluster = []int{}
+	}
+
+	end := time.Time{}
+	for _, i := range byStart(intervals) {
+		cur := intervals[i]
+		if len(cluster) > 0 && !end.After(cur.Start) {
+			flush()
+		}
+		if len(cluster) == 0 || cur.End.After(end) {
+			end = cur.End
+		}
+		cluster = append(cluster, i)
+	}
+	flush()
+	return r
+}
+
+// returns the indexes of the non empty intervals ordered by start, ties keep the input order
+func byStart(intervals []Interval) []int {
+	order := []int{}
+	for i, v := range intervals {
+		if v.Start.Before(v.End) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return intervals[order[a]].Start.Before(intervals[order[b]].Start) })
+	return order
+}
+
+func unlabel(intervals []LabeledInterval) []Interval {
+	r := make([]Interval, len(intervals))
+	for i, l := range intervals {
+		r[i] = l.Interval
+	}
+	return r
+}
@@ -0,0 +1,99 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindOverlaps(t *testing.T) {
+	// 0:  AAAAAAAAAA
+	// 1:       BBBBBBB
+	// 2:            CCCCC
+	// 3:                 DDD
+	// 4:   E (empty)
+
+	intervals := []Interval{testInterval(0, 10), testInterval(5, 12), testInterval(10, 15), testInterval(15, 18), testInterval(2, 2)}
+	overlaps := FindOverlaps(intervals)
+	assert.Equal(t, 2, len(overlaps), "result: %v", overlaps)
+
+	assert.Equal(t, 0, overlaps[0].I)
+	assert.Equal(t, 1, overlaps[0].J)
+	assert.Equal(t, "", intervalsDiff(testInterval(5, 10), overlaps[0].Interval))
+
+	// 0 and 2 only touch, as do 2 and 3
+	assert.Equal(t, 1, overlaps[1].I)
+	assert.Equal(t, 2, overlaps[1].J)
+	assert.Equal(t, "", intervalsDiff(testInterval(10, 12), overlaps[1].Interval))
+}
+
+func Test_FindLabeledOverlaps(t *testing.T) {
+	bookings := []LabeledInterval{
+		{Label: "standup", Interval: testInterval(30, 45)},
+		{Label: "planning", Interval: testInterval(0, 60)},
+		{Label: "lunch", Interval: testInterval(60, 120)},
+	}
+	overlaps := FindLabeledOverlaps(bookings)
+	assert.Equal(t, 1, len(overlaps), "result: %v", overlaps)
+	assert.Equal(t, "standup", overlaps[0].A.Label)
+	assert.Equal(t, "planning", overlaps[0].B.Label)
+	assert.Equal(t, "", intervalsDiff(testInterval(30, 45), overlaps[0].Interval))
+}
+
+func Test_OverlapClusters(t *testing.T) {
+	// 0:  AAAAA
+	// 1:     BBBBBB
+	// 2:           CC      (touches B)
+	// 3:                DDDDDDDD
+	// 4:                  EE
+	// 5:                      FF
+	// 6:                           GG
+
+	intervals := []Interval{
+		testInterval(0, 5), testInterval(3, 9), testInterval(9, 11), testInterval(14, 22),
+		testInterval(16, 18), testInterval(20, 22), testInterval(25, 27),
+	}
+	assert.Equal(t, [][]int{{0, 1}, {3, 4, 5}}, OverlapClusters(intervals))
+	assert.Equal(t, [][]int{}, OverlapClusters([]Interval{testInterval(0, 5), testInterval(5, 10)}))
+}
+
+func Test_FindOverlaps_MatchesBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for run := 0; run < 100; run++ {
+		intervals := randomGridIntervals(rnd, rnd.Intn(30))
+
+		expected := []Overlap{}
+		for i := range intervals {
+			for j := i + 1; j < len(intervals); j++ {
+				a, b := intervals[i], intervals[j]
+				if a.Start.Before(a.End) && b.Start.Before(b.End) && a.Overlaps(b) {
+					shared := Intersect([]Interval{a}, []Interval{b})
+					expected = append(expected, Overlap{I: i, J: j, Interval: shared[0]})
+				}
+			}
+		}
+
+		actual := FindOverlaps(intervals)
+		if !assert.Equal(t, len(expected), len(actual)) {
+			return
+		}
+		for k := range expected {
+			assert.Equal(t, expected[k].I, actual[k].I)
+			assert.Equal(t, expected[k].J, actual[k].J)
+			assert.Equal(t, "", intervalsDiff(expected[k].Interval, actual[k].Interval))
+		}
+
+		// every overlapping pair ends up in the same cluster
+		clusterOf := map[int]int{}
+		for c, cluster := range OverlapClusters(intervals) {
+			for _, i := range cluster {
+				clusterOf[i] = c + 1
+			}
+		}
+		for _, o := range actual {
+			assert.NotZero(t, clusterOf[o.I])
+			assert.Equal(t, clusterOf[o.I], clusterOf[o.J])
+		}
+	}
+}
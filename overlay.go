@@ -0,0 +1,101 @@
+package time_intervals
+
+import (
+	"sort"
+	"time"
+)
+
+type LayerMode string
+
+const LayerAdd LayerMode = "add"
+const LayerSubtract LayerMode = "subtract"
+const LayerReplace LayerMode = "replace"
+
+// Layer is one level of a layered schedule, e.g. the default weekly hours, a date specific override or a list of blocks.
+// LayerAdd makes its intervals available, LayerSubtract removes them and LayerReplace discards whatever the lower
+// layers resolved to inside Span and uses its own intervals there instead.
+type Layer struct {
+	Mode      LayerMode
+	Priority  int
+	Span      Interval // only used by LayerReplace
+	Intervals []Interval
+}
+
+type layerEndpoint struct {
+	Time  time.Time
+	Layer int
+	Span  bool
+	Delta int
+}
+
+// Overlay resolves the layers into ordered disjoint available intervals.
+// Layers with a higher priority win over the lower ones, layers with the same priority are applied in list order.
+// Like SubstractBlockedIntervals it sweeps over the sorted endpoints of all layers in O(n*log_n + n*layers).
+func Overlay(layers []Layer) []Interval {
+	order := make([]int, len(layers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return layers[order[i]].Priority < layers[order[j]].Priority })
+
+	endpoints := []layerEndpoint{}
+	for l, layer := range layers {
+		for _, i := range layer.Intervals {
+			endpoints = append(endpoints, layerEndpoint{i.Start, l, false, 1}, layerEndpoint{i.End, l, false, -1})
+		}
+		if layer.Mode == LayerReplace {
+			endpoints = append(endpoints, layerEndpoint{layer.Span.Start, l, true, 1}, layerEndpoint{layer.Span.End, l, true, -1})
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Time.Before(endpoints[j].Time) })
+
+	openIntervals := make([]int, len(layers))
+	openSpans := make([]int, len(layers))
+
+	results := []Interval{}
+	available := false
+	currentAvailableIntervalStart := time.Time{}
+	for i, e := range endpoints {
+		if e.Span {
+			openSpans[e.Layer] += e.Delta
+		} else {
+			openIntervals[e.Layer] += e.Delta
+		}
+		if i+1 < len(endpoints) && endpoints[i+1].Time.Equal(e.Time) {
+			// resolve only once all the endpoints at this time were applied
+			continue
+		}
+
+		nextAvailable := resolveLayers(layers, order, openIntervals, openSpans)
+		if !available && nextAvailable {
+			currentAvailableIntervalStart = e.Time
+		}
+		if available && !nextAvailable {
+			results = append(results, Interval{Start: currentAvailableIntervalStart, End: e.Time})
+		}
+		available = nextAvailable
+	}
+	return results
+}
+
+// the topmost layer that has an opinion about the current time decides if it is available
+func resolveLayers(layers []Layer, order []int, openIntervals []int, openSpans []int) bool {
+	for k := len(order) - 1; k >= 0; k-- {
+		l := order[k]
+		switch layers[l].Mode {
+		case LayerAdd:
+			if openIntervals[l] > 0 {
+				return true
+			}
+		case LayerSubtract:
+			if openIntervals[l] > 0 {
+				return false
+			}
+		case LayerReplace:
+			if openSpans[l] > 0 {
+				return openIntervals[l] > 0
+			}
+		}
+	}
+	return false
+}
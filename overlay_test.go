@@ -0,0 +1,55 @@
+package time_intervals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Overlay(t *testing.T) {
+	// day0     day1	day2
+	// |		|		|
+	//   HHHHHH   HHHHHH		weekly hours (add, priority 0)
+	//          [  OO  ]		override for day1 (replace, priority 1)
+	//      BB				    block (subtract, priority 2)
+	//   RRR  R    RR 			result
+
+	base := Layer{Mode: LayerAdd, Intervals: []Interval{testDHInterval(0, 9, 0, 17), testDHInterval(1, 9, 1, 17)}}
+	override := Layer{Mode: LayerReplace, Priority: 1, Span: testDHInterval(1, 0, 2, 0), Intervals: []Interval{testDHInterval(1, 10, 1, 12)}}
+	block := Layer{Mode: LayerSubtract, Priority: 2, Intervals: []Interval{testDHInterval(0, 14, 0, 15)}}
+
+	// the order of the list does not matter when the priorities differ
+	r := Overlay([]Layer{block, base, override})
+	assert.Equal(t, 3, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 9, 0, 14), r[0]))
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 15, 0, 17), r[1]))
+	assert.Equal(t, "", intervalsDiff(testDHInterval(1, 10, 1, 12), r[2]))
+}
+
+func Test_Overlay_SamePriorityUsesListOrder(t *testing.T) {
+	// AAAAAAAA  add
+	//   SSSS    subtract
+	//    AA     add again
+	// RR AA  RR
+
+	a := Layer{Mode: LayerAdd, Intervals: []Interval{testInterval(0, 8)}}
+	s := Layer{Mode: LayerSubtract, Intervals: []Interval{testInterval(2, 6)}}
+	again := Layer{Mode: LayerAdd, Intervals: []Interval{testInterval(3, 5)}}
+
+	r := Overlay([]Layer{a, s, again})
+	assert.Equal(t, 3, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 2), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(3, 5), r[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(6, 8), r[2]))
+
+	r = Overlay([]Layer{a, again, s})
+	assert.Equal(t, 2, len(r), "result: %v", r)
+}
+
+func Test_Overlay_MatchesSubstractBlockedIntervals(t *testing.T) {
+	available := []Interval{testInterval(1, 4), testInterval(3, 9), testInterval(12, 20)}
+	blocked := []Interval{testInterval(2, 3), testInterval(8, 14), testInterval(16, 17)}
+
+	r := Overlay([]Layer{{Mode: LayerAdd, Intervals: available}, {Mode: LayerSubtract, Priority: 1, Intervals: blocked}})
+	assertSameIntervals(t, SubstractBlockedIntervals(available, blocked), r)
+}
@@ -0,0 +1,156 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the inputs of the property tests live on a minute grid, so a brute force reference can simply check every minute
+const propertyGridMinutes = 80
+
+// reference implementation: a minute is free if an available interval covers it and no blocked interval does
+func bruteForceFreeMinutes(available []Interval, blocked []Interval) []bool {
+	free := make([]bool, propertyGridMinutes)
+	for m := range free {
+		cell := testInterval(m, m+1)
+		for _, a := range available {
+			if a.Contains(cell) {
+				free[m] = true
+			}
+		}
+		for _, b := range blocked {
+			if b.Contains(cell) {
+				free[m] = false
+			}
+		}
+	}
+	return free
+}
+
+func minutesToIntervals(minutes []bool) []Interval {
+	r := []Interval{}
+	for m := 0; m < len(minutes); m++ {
+		if !minutes[m] {
+			continue
+		}
+		s := m
+		for m < len(minutes) && minutes[m] {
+			m++
+		}
+		r = append(r, testInterval(s, m))
+	}
+	return r
+}
+
+func intervalsToMinutes(a []Interval) []bool {
+	minutes := make([]bool, propertyGridMinutes)
+	for m := range minutes {
+		for _, i := range a {
+			if i.Contains(testInterval(m, m+1)) {
+				minutes[m] = true
+			}
+		}
+	}
+	return minutes
+}
+
+func checkSweepProperties(t *testing.T, available []Interval, blocked []Interval) bool {
+	r := SubstractBlockedIntervals(available, blocked)
+	ok := true
+
+	// sorted, disjoint and without empty intervals
+	for k, i := range r {
+		ok = ok && assert.True(t, i.Start.Before(i.End), "empty interval %v in %v", i, r)
+		if k > 0 {
+			ok = ok && assert.False(t, i.Start.Before(r[k-1].End), "%v is not after %v", i, r[k-1])
+		}
+	}
+
+	// covers exactly the free minutes, so it is inside available and does not touch blocked
+	ok = ok && assert.Equal(t, bruteForceFreeMinutes(available, blocked), intervalsToMinutes(r), "available %v blocked %v result %v", available, blocked, r)
+
+	// adjacent results are only expected when an empty blocked interval splits them
+	emptyBlocked := false
+	for _, b := range blocked {
+		emptyBlocked = emptyBlocked || b.Start.Equal(b.End)
+	}
+	if !emptyBlocked {
+		ok = ok && assertSameIntervals(t, minutesToIntervals(bruteForceFreeMinutes(available, blocked)), r)
+	}
+
+	// the sort based sweep gives the same result, also when the inputs are in order and merged instead of sorted
+	ok = ok && assertSameIntervals(t, r, AppendSubstractBlockedIntervals(nil, available, blocked))
+	ordered := MergeAndReturnNonOverlappingIntervals(available)
+	ok = ok && assertSameIntervals(t, SubstractBlockedIntervals(ordered, blocked), AppendSubstractBlockedIntervals(nil, ordered, blocked))
+
+	// merging is idempotent
+	ok = ok && assertSameIntervals(t, ordered, MergeAndReturnNonOverlappingIntervals(ordered))
+	return ok
+}
+
+func randomGridIntervals(rnd *rand.Rand, n int) []Interval {
+	r := []Interval{}
+	for i := 0; i < n; i++ {
+		s := rnd.Intn(propertyGridMinutes - 20)
+		r = append(r, testInterval(s, s+rnd.Intn(20)))
+	}
+	return r
+}
+
+func Test_SubstractBlockedIntervals_Properties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for run := 0; run < 2000; run++ {
+		available := randomGridIntervals(rnd, rnd.Intn(8))
+		blocked := randomGridIntervals(rnd, rnd.Intn(8))
+		if !checkSweepProperties(t, available, blocked) {
+			return
+		}
+	}
+}
+
+func Test_SubstractBlockedIntervals_SharedEndpoints(t *testing.T) {
+	// every combination of intervals starting and ending on the same few minutes, to exercise the tie breaking
+	// in EndpointsHeap.Less
+	candidates := []Interval{}
+	for s := 0; s <= 3; s++ {
+		for e := s; e <= 3; e++ {
+			candidates = append(candidates, testInterval(s, e))
+		}
+	}
+	for _, a1 := range candidates {
+		for _, a2 := range candidates {
+			for _, b := range candidates {
+				if !checkSweepProperties(t, []Interval{a1, a2}, []Interval{b}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// decodes the fuzzer input into intervals on the minute grid, three bytes per interval
+func fuzzIntervals(data []byte) (available []Interval, blocked []Interval) {
+	available, blocked = []Interval{}, []Interval{}
+	for k := 0; k+2 < len(data); k += 3 {
+		s := int(data[k+1]) % (propertyGridMinutes - 20)
+		i := testInterval(s, s+int(data[k+2])%20)
+		if data[k]%2 == 0 {
+			available = append(available, i)
+		} else {
+			blocked = append(blocked, i)
+		}
+	}
+	return available, blocked
+}
+
+func FuzzSubstractBlockedIntervals(f *testing.F) {
+	f.Add([]byte{0, 1, 10, 1, 3, 2})
+	f.Add([]byte{0, 5, 5, 0, 10, 0, 1, 10, 0})
+	f.Add([]byte{0, 0, 19, 0, 19, 19, 1, 0, 4, 1, 4, 4, 1, 30, 19})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		available, blocked := fuzzIntervals(data)
+		checkSweepProperties(t, available, blocked)
+	})
+}
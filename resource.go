@@ -0,0 +1,137 @@
+package time_intervals
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-errors/errors"
+)
+
+var ErrInvalidInterval = errors.New("Interval start must be before its end")
+var ErrNotAvailable = errors.New("Requested interval is not inside the available time")
+var ErrBookingNotFound = errors.New("Booking not found")
+
+type Booking struct {
+	ID       string
+	Interval Interval
+}
+
+// returned by Resource.Reserve when the requested interval overlaps existing bookings
+type ConflictError struct {
+	Requested Interval
+	Conflicts []Booking
+}
+
+func (e *ConflictError) Error() string {
+	ids := make([]string, len(e.Conflicts))
+	for i, b := range e.Conflicts {
+		ids[i] = b.ID
+	}
+	return fmt.Sprintf("%v conflicts with bookings [%s]", e.Requested.String(), strings.Join(ids, ", "))
+}
+
+// Resource holds the available and blocked intervals of something that can be booked (a room, a person)
+// together with the bookings made against it. All methods are safe for concurrent use.
+type Resource struct {
+	mu        sync.Mutex
+	available []Interval
+	blocked   []Interval
+	bookings  map[string]Booking
+	nextID    int
+}
+
+func NewResource(available []Interval, blocked []Interval) *Resource {
+	return &Resource{
+		available: append([]Interval{}, available...),
+		blocked:   append([]Interval{}, blocked...),
+		bookings:  map[string]Booking{},
+	}
+}
+
+// Reserve books the interval if it is free and returns the booking id.
+// If it overlaps other bookings a *ConflictError listing them is returned; if it is not fully inside
+// the available minus blocked time ErrNotAvailable is returned. The check and the booking happen atomically.
+func (r *Resource) Reserve(i Interval) (string, error) {
+	if !i.Start.Before(i.End) {
+		return "", ErrInvalidInterval
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conflicts := []Booking{}
+	for _, b := range r.bookings {
+		if b.Interval.Overlaps(i) {
+			conflicts = append(conflicts, b)
+		}
+	}
+	if len(conflicts) > 0 {
+		sortBookings(conflicts)
+		return "", &ConflictError{Requested: i, Conflicts: conflicts}
+	}
+
+	inside := false
+	for _, f := range SubstractBlockedIntervals(r.available, r.blocked) {
+		if f.Contains(i) {
+			inside = true
+			break
+		}
+	}
+	if !inside {
+		return "", ErrNotAvailable
+	}
+
+	r.nextID++
+	id := strconv.Itoa(r.nextID)
+	r.bookings[id] = Booking{ID: id, Interval: i}
+	return id, nil
+}
+
+// Release removes a booking, making its interval free again
+func (r *Resource) Release(bookingID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.bookings[bookingID]; !ok {
+		return ErrBookingNotFound
+	}
+	delete(r.bookings, bookingID)
+	return nil
+}
+
+// FreeSlots returns the ordered disjoint intervals inside the window that are available, not blocked and not booked
+func (r *Resource) FreeSlots(window Interval) []Interval {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	busy := append([]Interval{}, r.blocked...)
+	for _, b := range r.bookings {
+		busy = append(busy, b.Interval)
+	}
+	return clipIntervals(SubstractBlockedIntervals(r.available, busy), window)
+}
+
+// Bookings returns the current bookings ordered by start time
+func (r *Resource) Bookings() []Booking {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]Booking, 0, len(r.bookings))
+	for _, b := range r.bookings {
+		res = append(res, b)
+	}
+	sortBookings(res)
+	return res
+}
+
+func sortBookings(b []Booking) {
+	sort.Slice(b, func(i, j int) bool {
+		if b[i].Interval.Start.Equal(b[j].Interval.Start) {
+			return b[i].ID < b[j].ID
+		}
+		return b[i].Interval.Start.Before(b[j].Interval.Start)
+	})
+}
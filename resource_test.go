@@ -0,0 +1,100 @@
+package time_intervals
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Resource_ReserveAndRelease(t *testing.T) {
+	// available:  AAAAAAAAAAAAAAAAAAAA
+	// blocked:          BBB
+	// bookings:   11  22      33
+
+	r := NewResource([]Interval{testInterval(0, 20)}, []Interval{testInterval(6, 9)})
+
+	id1, err := r.Reserve(testInterval(0, 2))
+	assert.NoError(t, err)
+	id2, err := r.Reserve(testInterval(3, 5))
+	assert.NoError(t, err)
+	_, err = r.Reserve(testInterval(12, 14))
+	assert.NoError(t, err)
+
+	// overlapping the blocked interval
+	_, err = r.Reserve(testInterval(5, 7))
+	assert.Equal(t, ErrNotAvailable, err)
+
+	// outside of the available interval
+	_, err = r.Reserve(testInterval(19, 21))
+	assert.Equal(t, ErrNotAvailable, err)
+
+	// touching bookings do not conflict
+	_, err = r.Reserve(testInterval(2, 3))
+	assert.NoError(t, err)
+
+	// overlapping two bookings
+	_, err = r.Reserve(testInterval(1, 4))
+	conflict, ok := err.(*ConflictError)
+	assert.True(t, ok, "expected a conflict error, got %v", err)
+	assert.Equal(t, 3, len(conflict.Conflicts))
+	assert.Equal(t, id1, conflict.Conflicts[0].ID)
+	assert.Equal(t, id2, conflict.Conflicts[2].ID)
+
+	assert.NoError(t, r.Release(id2))
+	assert.Equal(t, ErrBookingNotFound, r.Release(id2))
+
+	_, err = r.Reserve(testInterval(3, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(r.Bookings()))
+}
+
+func Test_Resource_FreeSlots(t *testing.T) {
+	// available:  AAAAAAAAAAAAAAAAAAAA
+	// blocked:          BBB
+	// bookings:     11          22
+	// window:        WWWWWWWWWWWWWWW
+
+	r := NewResource([]Interval{testInterval(0, 20)}, []Interval{testInterval(6, 9)})
+	_, err := r.Reserve(testInterval(2, 4))
+	assert.NoError(t, err)
+	_, err = r.Reserve(testInterval(12, 14))
+	assert.NoError(t, err)
+
+	free := r.FreeSlots(testInterval(3, 18))
+	assert.Equal(t, 3, len(free), "result: %v", free)
+	assert.Equal(t, "", intervalsDiff(testInterval(4, 6), free[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(9, 12), free[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(14, 18), free[2]))
+}
+
+func Test_Resource_ConcurrentReserve(t *testing.T) {
+	// run with -race: many goroutines fight for the same slots, every slot must be booked exactly once
+	r := NewResource([]Interval{testInterval(0, 60)}, []Interval{})
+
+	const workers = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	won := map[int]int{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := 0; s < 60; s += 5 {
+				if _, err := r.Reserve(testInterval(s, s+5)); err == nil {
+					mu.Lock()
+					won[s]++
+					mu.Unlock()
+				}
+			}
+			r.FreeSlots(testInterval(0, 60))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 12, len(won))
+	for s, n := range won {
+		assert.Equal(t, 1, n, "slot %d was booked %d times", s, n)
+	}
+	assert.Equal(t, 0, len(r.FreeSlots(testInterval(0, 60))))
+}
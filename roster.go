@@ -0,0 +1,218 @@
+package time_intervals
+
+import (
+	"sort"
+	"time"
+)
+
+// DepthInterval is one step of a step function, e.g. the number of people needed or present during Interval
+type DepthInterval struct {
+	Interval Interval
+	Depth    int
+}
+
+type depthDelta struct {
+	Time  time.Time
+	Delta int
+}
+
+// CoverageDepth returns how many of the intervals cover each part of the time as ordered steps.
+// Parts that are not covered at all are left out.
+func CoverageDepth(a []Interval) []DepthInterval {
+	deltas := []depthDelta{}
+	for _, i := range a {
+		deltas = append(deltas, depthDelta{i.Start, 1}, depthDelta{i.End, -1})
+	}
+	return depthSteps(deltas)
+}
+
+// CompareCoverage returns where the actual depth is below the required one (under) and where it is above (over),
+// with the missing or extra depth of each step. Overlapping steps of the same input add up.
+func CompareCoverage(required []DepthInterval, actual []DepthInterval) (under []DepthInterval, over []DepthInterval) {
+	deltas := []depthDelta{}
+	for _, r := range required {
+		deltas = append(deltas, depthDelta{r.Interval.Start, r.Depth}, depthDelta{r.Interval.End, -r.Depth})
+	}
+	for _, a := range actual {
+		deltas = append(deltas, depthDelta{a.Interval.Start, -a.Depth}, depthDelta{a.Interval.End, a.Depth})
+	}
+
+	under, over = []DepthInterval{}, []DepthInterval{}
+	for _, s := range depthSteps(deltas) {
+		if s.Depth > 0 {
+			under = append(under, s)
+		} else {
+			over = append(over, DepthInterval{Interval: s.Interval, Depth: -s.Depth})
+		}
+	}
+	return under, over
+}
+
+// sweeps over the deltas and returns the steps with a non zero depth, joining neighbours with the same depth
+func depthSteps(deltas []depthDelta) []DepthInterval {
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Time.Before(deltas[j].Time) })
+
+	r := []DepthInterval{}
+	depth := 0
+	for k, d := range deltas {
+		depth += d.Delta
+		if k+1 == len(deltas) || deltas[k+1].Time.Equal(d.Time) || depth == 0 {
+			continue
+		}
+		step := Interval{Start: d.Time, End: deltas[k+1].Time}
+		if n := len(r); n > 0 && r[n-1].Depth == depth && r[n-1].Interval.End.Equal(step.Start) {
+			r[n-1].Interval.End = step.End
+		} else {
+			r = append(r, DepthInterval{Interval: step, Depth: depth})
+		}
+	}
+	return r
+}
+
+type Person struct {
+	ID   string
+	Free []Interval
+}
+
+type RosterRules struct {
+	MaxDailyHours time.Duration // most a person can work in a day, days are the ones of IntervalsByDay. Zero means no limit
+	MinRest       time.Duration // least time between two shifts of the same person
+	Granularity   time.Duration // the requirement is staffed in pieces of at most this length, defaults to an hour
+}
+
+type Assignment struct {
+	PersonID string
+	Interval Interval
+}
+
+type Roster struct {
+	Assignments []Assignment // ordered by start
+	Uncovered   []DepthInterval
+	OverCovered []DepthInterval
+}
+
+// ProposeRoster greedily assigns people to the required coverage. The requirement is cut at every day boundary and
+// at every start and end of the free time of the people, then in pieces of at most rules.Granularity; the pieces are
+// staffed in time order. People who can extend
+// their current shift are picked first, then the ones who worked the least that day, so the shifts stay long and the
+// work spread out. Nobody is assigned outside their free time, over MaxDailyHours or with less than MinRest between
+// two shifts. The parts that could not be staffed are returned as Uncovered.
+func ProposeRoster(required []DepthInterval, people []Person, rules RosterRules) Roster {
+	free := make([][]Interval, len(people))
+	cuts := []time.Time{}
+	for k, p := range people {
+		free[k] = MergeAndReturnNonOverlappingIntervals(p.Free)
+		for _, f := range free[k] {
+			cuts = append(cuts, f.Start, f.End)
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Before(cuts[j]) })
+	if rules.Granularity <= 0 {
+		rules.Granularity = time.Hour
+	}
+
+	segments := []DepthInterval{}
+	for _, step := range depthStepsOf(required) {
+		pieces := []Interval{}
+		splitByDay(step.Interval, func(sameDayInterval Interval) bool {
+			pieces = append(pieces, sameDayInterval)
+			return true
+		})
+		for _, piece := range joinDaySplits(pieces) {
+			start := piece.Start
+			for k := sort.Search(len(cuts), func(k int) bool { return cuts[k].After(piece.Start) }); start.Before(piece.End); k++ {
+				end := piece.End
+				if k < len(cuts) && cuts[k].Before(end) {
+					end = cuts[k]
+				}
+				if !end.After(start) {
+					continue // same cut as the previous one
+				}
+				for ; start.Before(end); start = start.Add(rules.Granularity) {
+					e := start.Add(rules.Granularity)
+					if e.After(end) {
+						e = end
+					}
+					segments = append(segments, DepthInterval{Interval: Interval{Start: start, End: e}, Depth: step.Depth})
+				}
+				start = end
+			}
+		}
+	}
+
+	lastShift := make([]int, len(people)) // index in assignments of the latest shift of each person, -1 if none
+	for k := range lastShift {
+		lastShift[k] = -1
+	}
+	worked := make([]map[time.Time]time.Duration, len(people))
+	for k := range worked {
+		worked[k] = map[time.Time]time.Duration{}
+	}
+
+	assignments := []Assignment{}
+	for _, seg := range segments {
+		day := NormalizeDate(seg.Interval.Start)
+		length := seg.Interval.End.Sub(seg.Interval.Start)
+
+		candidates := []int{}
+		for k := range people {
+			if !containedIn(free[k], seg.Interval) {
+				continue
+			}
+			if rules.MaxDailyHours > 0 && worked[k][day]+length > rules.MaxDailyHours {
+				continue
+			}
+			if s := lastShift[k]; s >= 0 {
+				end := assignments[s].Interval.End
+				if !end.Equal(seg.Interval.Start) && seg.Interval.Start.Sub(end) < rules.MinRest {
+					continue
+				}
+			}
+			candidates = append(candidates, k)
+		}
+
+		continues := func(k int) bool {
+			return lastShift[k] >= 0 && assignments[lastShift[k]].Interval.End.Equal(seg.Interval.Start)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if continues(a) != continues(b) {
+				return continues(a)
+			}
+			return worked[a][day] < worked[b][day]
+		})
+
+		for n := 0; n < seg.Depth && n < len(candidates); n++ {
+			k := candidates[n]
+			if continues(k) {
+				assignments[lastShift[k]].Interval.End = seg.Interval.End
+			} else {
+				assignments = append(assignments, Assignment{PersonID: people[k].ID, Interval: seg.Interval})
+				lastShift[k] = len(assignments) - 1
+			}
+			worked[k][day] += length
+		}
+	}
+
+	sort.SliceStable(assignments, func(i, j int) bool { return assignments[i].Interval.Start.Before(assignments[j].Interval.Start) })
+	shifts := []Interval{}
+	for _, a := range assignments {
+		shifts = append(shifts, a.Interval)
+	}
+	under, over := CompareCoverage(required, CoverageDepth(shifts))
+	return Roster{Assignments: assignments, Uncovered: under, OverCovered: over}
+}
+
+// normalizes possibly overlapping requirements into ordered steps
+func depthStepsOf(a []DepthInterval) []DepthInterval {
+	deltas := []depthDelta{}
+	for _, d := range a {
+		deltas = append(deltas, depthDelta{d.Interval.Start, d.Depth}, depthDelta{d.Interval.End, -d.Depth})
+	}
+	return depthSteps(deltas)
+}
+
+func containedIn(orderedDisjointIntervals []Interval, i Interval) bool {
+	k := sort.Search(len(orderedDisjointIntervals), func(k int) bool { return orderedDisjointIntervals[k].End.After(i.Start) })
+	return k < len(orderedDisjointIntervals) && orderedDisjointIntervals[k].Contains(i)
+}
@@ -0,0 +1,94 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CoverageDepth(t *testing.T) {
+	// AAAAAA
+	//   BBBBBB
+	//   CC
+	// 113221 1
+
+	d := CoverageDepth([]Interval{testInterval(0, 6), testInterval(2, 8), testInterval(2, 4), testInterval(9, 10)})
+	assert.Equal(t, []DepthInterval{
+		{Interval: testInterval(0, 2), Depth: 1},
+		{Interval: testInterval(2, 4), Depth: 3},
+		{Interval: testInterval(4, 6), Depth: 2},
+		{Interval: testInterval(6, 8), Depth: 1},
+		{Interval: testInterval(9, 10), Depth: 1},
+	}, d)
+}
+
+func Test_CompareCoverage(t *testing.T) {
+	// required: 2222222222
+	// actual:   1133332
+	// under:    11      22
+	// over:       1111
+
+	required := []DepthInterval{{Interval: testInterval(0, 10), Depth: 2}}
+	actual := []DepthInterval{{Interval: testInterval(0, 2), Depth: 1}, {Interval: testInterval(2, 6), Depth: 3}, {Interval: testInterval(6, 8), Depth: 2}}
+
+	under, over := CompareCoverage(required, actual)
+	assert.Equal(t, []DepthInterval{{Interval: testInterval(0, 2), Depth: 1}, {Interval: testInterval(8, 10), Depth: 2}}, under)
+	assert.Equal(t, []DepthInterval{{Interval: testInterval(2, 6), Depth: 1}}, over)
+}
+
+func Test_ProposeRoster(t *testing.T) {
+	// 9am -> 5pm one person needed, nobody can work more than 4 hours a day
+	// ana:   999999999999999999
+	// bob:   9999      99999999
+	required := []DepthInterval{{Interval: testDHInterval(0, 9, 0, 17), Depth: 1}}
+	people := []Person{
+		{ID: "ana", Free: []Interval{testDHInterval(0, 9, 0, 17)}},
+		{ID: "bob", Free: []Interval{testDHInterval(0, 9, 0, 11), testDHInterval(0, 14, 0, 17)}},
+	}
+
+	r := ProposeRoster(required, people, RosterRules{MaxDailyHours: 4 * time.Hour})
+	assert.Equal(t, []Assignment{
+		{PersonID: "ana", Interval: testDHInterval(0, 9, 0, 13)},
+		{PersonID: "bob", Interval: testDHInterval(0, 14, 0, 17)},
+	}, r.Assignments)
+	assert.Equal(t, []DepthInterval{{Interval: testDHInterval(0, 13, 0, 14), Depth: 1}}, r.Uncovered)
+	assert.Equal(t, 0, len(r.OverCovered))
+}
+
+func Test_ProposeRoster_MinRest(t *testing.T) {
+	required := []DepthInterval{{Interval: testDHInterval(0, 9, 0, 10), Depth: 1}, {Interval: testDHInterval(0, 11, 0, 12), Depth: 1}}
+	people := []Person{{ID: "ana", Free: []Interval{testDHInterval(0, 8, 0, 20)}}}
+
+	r := ProposeRoster(required, people, RosterRules{MinRest: 2 * time.Hour})
+	assert.Equal(t, []Assignment{{PersonID: "ana", Interval: testDHInterval(0, 9, 0, 10)}}, r.Assignments)
+	assert.Equal(t, []DepthInterval{{Interval: testDHInterval(0, 11, 0, 12), Depth: 1}}, r.Uncovered)
+
+	r = ProposeRoster(required, people, RosterRules{MinRest: time.Hour})
+	assert.Equal(t, 2, len(r.Assignments))
+	assert.Equal(t, 0, len(r.Uncovered))
+}
+
+func Test_ProposeRoster_NightShiftsAndDepth(t *testing.T) {
+	// two people needed from 10pm to 6am, the shifts run over midnight and the daily limit counts per day:
+	// ana and bob can only work until 5am, cid and dan take over
+	required := []DepthInterval{{Interval: testDHInterval(0, 22, 1, 6), Depth: 2}}
+	people := []Person{
+		{ID: "ana", Free: []Interval{testDHInterval(0, 20, 1, 8)}},
+		{ID: "bob", Free: []Interval{testDHInterval(0, 22, 1, 6)}},
+		{ID: "cid", Free: []Interval{testDHInterval(0, 0, 2, 0)}},
+		{ID: "dan", Free: []Interval{testDHInterval(1, 4, 1, 8)}},
+	}
+
+	r := ProposeRoster(required, people, RosterRules{MaxDailyHours: 5 * time.Hour})
+	assert.Equal(t, 0, len(r.Uncovered), "roster: %+v", r)
+	for _, a := range r.Assignments {
+		for _, p := range people {
+			if p.ID == a.PersonID {
+				assert.True(t, containedIn(p.Free, a.Interval), "%v is outside the free time of %s", a.Interval, p.ID)
+			}
+		}
+	}
+	assert.Equal(t, "ana", r.Assignments[0].PersonID)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 22, 1, 5), r.Assignments[0].Interval))
+}
@@ -0,0 +1,140 @@
+package time_intervals
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// WeightedInterval is a candidate booking with the value of accepting it
+type WeightedInterval struct {
+	Label    string
+	Interval Interval
+	Weight   float64
+}
+
+// MaxNonOverlapping picks the largest number of intervals that do not overlap each other, e.g. which of the
+// competing bookings of a room to accept. It is the greedy earliest end algorithm: always accepting the interval that
+// ends first leaves the most room for the rest. Touching intervals can both be accepted, empty intervals are ignored.
+// The result is ordered by time.
+func MaxNonOverlapping(intervals []LabeledInterval) []LabeledInterval {
+	order := nonEmptyByEnd(len(intervals), func(k int) Interval { return intervals[k].Interval })
+
+	r := []LabeledInterval{}
+	for _, k := range order {
+		if n := len(r); n == 0 || !intervals[k].Interval.Start.Before(r[n-1].Interval.End) {
+			r = append(r, intervals[k])
+		}
+	}
+	return r
+}
+
+// MaxWeightNonOverlapping picks the non overlapping intervals with the largest total weight and returns them
+// ordered by time together with that total. Intervals without a positive weight are never picked.
+// With the intervals ordered by end, best[j] = max(best[j-1], weight[j] + best[p]) where p is the number of intervals
+// that end before interval j starts, found with a binary search, so it runs in O(n log n).
+func MaxWeightNonOverlapping(intervals []WeightedInterval) ([]WeightedInterval, float64) {
+	order := nonEmptyByEnd(len(intervals), func(k int) Interval { return intervals[k].Interval })
+
+	// best[j] is the largest total weight using only the first j intervals of order
+	best := make([]float64, len(order)+1)
+	previous := make([]int, len(order))
+	for j, k := range order {
+		start := intervals[k].Interval.Start
+		previous[j] = sort.Search(j, func(p int) bool { return intervals[order[p]].Interval.End.After(start) })
+
+		best[j+1] = best[j]
+		if w := intervals[k].Weight + best[previous[j]]; w > best[j] {
+			best[j+1] = w
+		}
+	}
+
+	r := []WeightedInterval{}
+	for j := len(order); j > 0; {
+		if best[j] == best[j-1] {
+			j--
+			continue
+		}
+		r = append(r, intervals[order[j-1]])
+		j = previous[j-1]
+	}
+	for a, b := 0, len(r)-1; a < b; a, b = a+1, b-1 {
+		r[a], r[b] = r[b], r[a]
+	}
+	return r, best[len(order)]
+}
+
+// the busy lanes during AssignLanes, the first one is free the earliest
+type laneEnd struct {
+	End  time.Time
+	Lane int
+}
+
+type laneHeap []laneEnd
+
+func (h laneHeap) Len() int { return len(h) }
+func (h laneHeap) Less(i, j int) bool {
+	if h[i].End.Equal(h[j].End) {
+		return h[i].Lane < h[j].Lane
+	}
+	return h[i].End.Before(h[j].End)
+}
+func (h laneHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *laneHeap) Push(x interface{}) {
+	*h = append(*h, x.(laneEnd))
+}
+
+func (h *laneHeap) Pop() interface{} {
+	oldh := *h
+	x := oldh[len(oldh)-1]
+	*h = oldh[0 : len(oldh)-1]
+	return x
+}
+
+// AssignLanes puts every interval in a lane (a room, a row of a chart) so that the intervals of a lane do not
+// overlap, using as few lanes as possible. It returns the lane of each input interval and the number of lanes, which
+// is the highest depth of CoverageDepth. Empty intervals get lane -1.
+//
+// The intervals are visited by start like the endpoints of EndpointsHeap, but at equal times an end goes first here:
+// EndpointsHeap opens the next interval first to merge adjacent intervals, while a lane whose interval ends at t can
+// already take an interval starting at t.
+func AssignLanes(intervals []LabeledInterval) ([]int, int) {
+	lanes := make([]int, len(intervals))
+	order := []int{}
+	for k, l := range intervals {
+		lanes[k] = -1
+		if l.Interval.Start.Before(l.Interval.End) {
+			order = append(order, k)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return intervals[order[a]].Interval.Start.Before(intervals[order[b]].Interval.Start)
+	})
+
+	busy := &laneHeap{}
+	count := 0
+	for _, k := range order {
+		i := intervals[k].Interval
+		if busy.Len() > 0 && !(*busy)[0].End.After(i.Start) {
+			lanes[k] = heap.Pop(busy).(laneEnd).Lane
+		} else {
+			lanes[k] = count
+			count++
+		}
+		heap.Push(busy, laneEnd{End: i.End, Lane: lanes[k]})
+	}
+	return lanes, count
+}
+
+// returns the indexes of the non empty intervals ordered by end, ties keep the input order
+func nonEmptyByEnd(n int, interval func(k int) Interval) []int {
+	order := []int{}
+	for k := 0; k < n; k++ {
+		if i := interval(k); i.Start.Before(i.End) {
+			order = append(order, k)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return interval(order[a]).End.Before(interval(order[b]).End) })
+	return order
+}
@@ -0,0 +1,131 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MaxNonOverlapping(t *testing.T) {
+	// long:   LLLLLLLLLLLLLLLLLLLL
+	// a:      AAAA
+	// b:          BBBB
+	// c:            CCCC
+	// d:                DDDD
+
+	bookings := []LabeledInterval{
+		{Label: "long", Interval: testInterval(0, 20)},
+		{Label: "c", Interval: testInterval(6, 10)},
+		{Label: "a", Interval: testInterval(0, 4)},
+		{Label: "b", Interval: testInterval(4, 8)},
+		{Label: "d", Interval: testInterval(10, 14)},
+	}
+	accepted := MaxNonOverlapping(bookings)
+	labels := []string{}
+	for _, a := range accepted {
+		labels = append(labels, a.Label)
+	}
+	assert.Equal(t, []string{"a", "b", "d"}, labels)
+}
+
+func Test_MaxWeightNonOverlapping(t *testing.T) {
+	// the long booking is worth more than the three short ones together
+	bookings := []WeightedInterval{
+		{Label: "a", Interval: testInterval(0, 4), Weight: 2},
+		{Label: "b", Interval: testInterval(4, 8), Weight: 2},
+		{Label: "long", Interval: testInterval(0, 20), Weight: 7},
+		{Label: "d", Interval: testInterval(10, 14), Weight: 2},
+		{Label: "free", Interval: testInterval(20, 30), Weight: 0},
+	}
+	accepted, total := MaxWeightNonOverlapping(bookings)
+	assert.Equal(t, 7.0, total)
+	assert.Equal(t, 1, len(accepted))
+	assert.Equal(t, "long", accepted[0].Label)
+
+	bookings[2].Weight = 5
+	accepted, total = MaxWeightNonOverlapping(bookings)
+	assert.Equal(t, 6.0, total)
+	assert.Equal(t, 3, len(accepted))
+	assert.Equal(t, "a", accepted[0].Label)
+	assert.Equal(t, "d", accepted[2].Label)
+}
+
+func Test_AssignLanes(t *testing.T) {
+	// lane 0:  AAAA CCCCEE
+	// lane 1:    BBBBDDD
+
+	intervals := []LabeledInterval{
+		{Label: "a", Interval: testInterval(0, 4)},
+		{Label: "b", Interval: testInterval(2, 6)},
+		{Label: "c", Interval: testInterval(5, 9)},
+		{Label: "d", Interval: testInterval(6, 9)},
+		{Label: "e", Interval: testInterval(9, 11)},
+		{Label: "empty", Interval: testInterval(3, 3)},
+	}
+	lanes, n := AssignLanes(intervals)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []int{0, 1, 0, 1, 0, -1}, lanes)
+}
+
+func Test_Scheduling_MatchesBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	for run := 0; run < 200; run++ {
+		intervals := randomGridIntervals(rnd, rnd.Intn(12))
+		labeled := make([]LabeledInterval, len(intervals))
+		weighted := make([]WeightedInterval, len(intervals))
+		for k, i := range intervals {
+			labeled[k] = LabeledInterval{Interval: i}
+			weighted[k] = WeightedInterval{Interval: i, Weight: float64(rnd.Intn(10))}
+		}
+
+		// try every subset of the non empty intervals
+		maxCount, maxWeight := 0, 0.0
+		for set := 0; set < 1<<len(intervals); set++ {
+			count, weight, ok := 0, 0.0, true
+			for a := range intervals {
+				if set&(1<<a) == 0 {
+					continue
+				}
+				if !intervals[a].Start.Before(intervals[a].End) {
+					ok = false
+				}
+				for b := a + 1; b < len(intervals); b++ {
+					if set&(1<<b) != 0 && intervals[a].Overlaps(intervals[b]) {
+						ok = false
+					}
+				}
+				count++
+				weight += weighted[a].Weight
+			}
+			if ok {
+				maxCount = max(maxCount, count)
+				maxWeight = max(maxWeight, weight)
+			}
+		}
+
+		accepted := MaxNonOverlapping(labeled)
+		assert.Equal(t, maxCount, len(accepted))
+		assert.Empty(t, FindOverlaps(unlabel(accepted)))
+
+		chosen, total := MaxWeightNonOverlapping(weighted)
+		assert.Equal(t, maxWeight, total)
+		sum := 0.0
+		for _, c := range chosen {
+			sum += c.Weight
+			assert.True(t, c.Weight > 0)
+		}
+		assert.Equal(t, total, sum)
+
+		// as many lanes as the deepest coverage, and no overlaps inside a lane
+		lanes, n := AssignLanes(labeled)
+		depth := 0
+		for _, d := range CoverageDepth(intervals) {
+			depth = max(depth, d.Depth)
+		}
+		assert.Equal(t, depth, n)
+		for _, o := range FindOverlaps(intervals) {
+			assert.NotEqual(t, lanes[o.I], lanes[o.J], "%v and %v share a lane", intervals[o.I], intervals[o.J])
+		}
+	}
+}
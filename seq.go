@@ -0,0 +1,125 @@
+package time_intervals
+
+import (
+	"container/heap"
+	"iter"
+	"time"
+)
+
+// min heap of the end times of the intervals that are currently open
+type endTimesHeap []time.Time
+
+func (h endTimesHeap) Len() int           { return len(h) }
+func (h endTimesHeap) Less(i, j int) bool { return h[i].Before(h[j]) }
+func (h endTimesHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *endTimesHeap) Push(x interface{}) {
+	*h = append(*h, x.(time.Time))
+}
+
+func (h *endTimesHeap) Pop() interface{} {
+	oldh := *h
+	x := oldh[len(oldh)-1]
+	*h = oldh[0 : len(oldh)-1]
+	return x
+}
+
+// SubstractBlockedSeq is the lazy form of SubstractBlockedIntervals. Both streams must be ordered by start, but the
+// intervals may overlap. It only keeps the end times of the currently open intervals in memory and yields every
+// resulting interval, in order, as soon as it is complete.
+func SubstractBlockedSeq(available iter.Seq[Interval], blocked iter.Seq[Interval]) iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		nextAvailable, stopAvailable := iter.Pull(available)
+		defer stopAvailable()
+		nextBlocked, stopBlocked := iter.Pull(blocked)
+		defer stopBlocked()
+
+		a, aok := nextAvailable()
+		b, bok := nextBlocked()
+		availableEnds := &endTimesHeap{}
+		blockedEnds := &endTimesHeap{}
+
+		currentAvailableIntervalStart := time.Time{}
+		for {
+			// pick the earliest endpoint, starts go before ends like in EndpointsHeap
+			e := Endpoint{}
+			found := false
+			consider := func(c Endpoint) {
+				if !found || compareEndpoints(c, e) < 0 {
+					e, found = c, true
+				}
+			}
+			if aok {
+				consider(Endpoint{Available, Start, a.Start})
+			}
+			if bok {
+				consider(Endpoint{Blocked, Start, b.Start})
+			}
+			if availableEnds.Len() > 0 {
+				consider(Endpoint{Available, End, (*availableEnds)[0]})
+			}
+			if blockedEnds.Len() > 0 {
+				consider(Endpoint{Blocked, End, (*blockedEnds)[0]})
+			}
+			if !found {
+				return
+			}
+
+			wasFree := availableEnds.Len() > 0 && blockedEnds.Len() == 0
+			switch {
+			case e.IntervalType == Available && e.EndpointType == Start:
+				heap.Push(availableEnds, a.End)
+				a, aok = nextAvailable()
+			case e.IntervalType == Blocked && e.EndpointType == Start:
+				heap.Push(blockedEnds, b.End)
+				b, bok = nextBlocked()
+			case e.IntervalType == Available:
+				heap.Pop(availableEnds)
+			default:
+				heap.Pop(blockedEnds)
+			}
+			isFree := availableEnds.Len() > 0 && blockedEnds.Len() == 0
+
+			if !wasFree && isFree {
+				currentAvailableIntervalStart = e.Time
+			}
+			if wasFree && !isFree && currentAvailableIntervalStart.Before(e.Time) {
+				if !yield(Interval{Start: currentAvailableIntervalStart, End: e.Time}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MergeSeq is the lazy form of MergeAndReturnNonOverlappingIntervals for a stream ordered by start
+func MergeSeq(a iter.Seq[Interval]) iter.Seq[Interval] {
+	return SubstractBlockedSeq(a, func(yield func(Interval) bool) {})
+}
+
+// SplitByDaySeq is the lazy form of IntervalsByDay, it yields each same day interval together with its day.
+// For a stream of ordered disjoint intervals the output is ordered too.
+func SplitByDaySeq(a iter.Seq[Interval]) iter.Seq2[time.Time, Interval] {
+	return func(yield func(time.Time, Interval) bool) {
+		for i := range a {
+			ok := splitByDay(i, func(sameDayInterval Interval) bool {
+				return yield(NormalizeDate(sameDayInterval.Start), sameDayInterval)
+			})
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// SplitInFixedIntervalsSeq is the lazy form of SplitInFixedIntervals
+func SplitInFixedIntervalsSeq(orderedDisjointIntervals iter.Seq[Interval], intervalLengthInMinutes int) iter.Seq[Interval] {
+	l := time.Duration(intervalLengthInMinutes) * time.Minute
+	return func(yield func(Interval) bool) {
+		for i := range orderedDisjointIntervals {
+			if !splitInFixedIntervals(i, l, yield) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedByStart(a []Interval) []Interval {
+	sort.Slice(a, func(i, j int) bool { return a[i].Start.Before(a[j].Start) })
+	return a
+}
+
+func Test_SubstractBlockedSeq_MatchesSlices(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	for run := 0; run < 1000; run++ {
+		available := sortedByStart(randomGridIntervals(rnd, rnd.Intn(8)))
+		blocked := sortedByStart(randomGridIntervals(rnd, rnd.Intn(8)))
+
+		expected := SubstractBlockedIntervals(available, blocked)
+		actual := slices.Collect(SubstractBlockedSeq(slices.Values(available), slices.Values(blocked)))
+		if !assertSameIntervals(t, expected, actual) {
+			t.Logf("available %v blocked %v", available, blocked)
+			return
+		}
+		assertSameIntervals(t, MergeAndReturnNonOverlappingIntervals(available), slices.Collect(MergeSeq(slices.Values(available))))
+	}
+}
+
+func Test_Seq_Pipeline(t *testing.T) {
+	// 2 days of availability minus a block, split by day and then in 4 hour slots
+	available := []Interval{testDHInterval(0, 8, 1, 16)}
+	blocked := []Interval{testDHInterval(0, 12, 0, 14)}
+
+	free := SubstractBlockedSeq(slices.Values(available), slices.Values(blocked))
+
+	days := []time.Time{}
+	pieces := []Interval{}
+	for d, i := range SplitByDaySeq(free) {
+		days = append(days, d)
+		pieces = append(pieces, i)
+	}
+	assert.Equal(t, []time.Time{baseTime, baseTime, baseTime.AddDate(0, 0, 1)}, days)
+	e := testDHInterval(0, 14, 1, 0)
+	e.End = e.End.Add(-time.Millisecond)
+	assert.Equal(t, "", intervalsDiff(e, pieces[1]))
+
+	slots := slices.Collect(SplitInFixedIntervalsSeq(slices.Values(pieces), 4*60))
+	assertSameIntervals(t, SplitInFixedIntervals(pieces, 4*60), slots)
+	assert.Equal(t, 1+2+4, len(slots))
+}
+
+func Test_SubstractBlockedSeq_IsLazy(t *testing.T) {
+	// an endless stream of available hours, blocked every other hour
+	hours := func(offset int) func(func(Interval) bool) {
+		return func(yield func(Interval) bool) {
+			for h := offset; ; h += 2 {
+				if !yield(testDHInterval(0, h, 0, h+1)) {
+					return
+				}
+			}
+		}
+	}
+
+	n := 0
+	for i := range SubstractBlockedSeq(MergeSeq(hours(0)), hours(1)) {
+		assert.Equal(t, time.Hour, i.End.Sub(i.Start))
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, n)
+}
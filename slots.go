@@ -0,0 +1,113 @@
+package time_intervals
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+var ErrSlotNotFound = errors.New("No slot starts at the given time")
+var ErrNotEnoughSeats = errors.New("Not enough seats left in the slot")
+var ErrInvalidSeats = errors.New("Number of seats must be positive")
+
+type Slot struct {
+	Interval Interval
+	Capacity int // seats of the slot after the blocked intervals were applied
+	Booked   int
+}
+
+func (s Slot) Remaining() int {
+	return s.Capacity - s.Booked
+}
+
+// SlotGrid tracks the booked seats of the fixed length slots generated by SplitInFixedIntervals.
+// All methods are safe for concurrent use.
+type SlotGrid struct {
+	mu    sync.Mutex
+	slots []Slot
+}
+
+// NewSlotGrid splits the merged available intervals in slots with capacity seats each. Every blocked interval
+// overlapping a slot takes one seat away from it, so a single blocked interval closes slots with one seat.
+func NewSlotGrid(available []Interval, intervalLengthInMinutes int, capacity int, blocked []Interval) *SlotGrid {
+	g := &SlotGrid{slots: []Slot{}}
+	for _, i := range SplitInFixedIntervals(MergeAndReturnNonOverlappingIntervals(available), intervalLengthInMinutes) {
+		c := capacity
+		for _, b := range blocked {
+			if b.Overlaps(i) {
+				c--
+			}
+		}
+		if c < 0 {
+			c = 0
+		}
+		g.slots = append(g.slots, Slot{Interval: i, Capacity: c})
+	}
+	return g
+}
+
+// Book takes seats in the slot starting at start
+func (g *SlotGrid) Book(start time.Time, seats int) error {
+	if seats <= 0 {
+		return ErrInvalidSeats
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	k, ok := g.find(start)
+	if !ok {
+		return ErrSlotNotFound
+	}
+	if g.slots[k].Remaining() < seats {
+		return ErrNotEnoughSeats
+	}
+	g.slots[k].Booked += seats
+	return nil
+}
+
+// Cancel gives back seats booked in the slot starting at start
+func (g *SlotGrid) Cancel(start time.Time, seats int) error {
+	if seats <= 0 {
+		return ErrInvalidSeats
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	k, ok := g.find(start)
+	if !ok {
+		return ErrSlotNotFound
+	}
+	if g.slots[k].Booked < seats {
+		return errors.New("Can not cancel more seats than were booked")
+	}
+	g.slots[k].Booked -= seats
+	return nil
+}
+
+// SlotsWithSeats returns, in order, the slots with at least n seats left
+func (g *SlotGrid) SlotsWithSeats(n int) []Slot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r := []Slot{}
+	for _, s := range g.slots {
+		if s.Remaining() >= n {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func (g *SlotGrid) Slots() []Slot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]Slot{}, g.slots...)
+}
+
+func (g *SlotGrid) find(start time.Time) (int, bool) {
+	k := sort.Search(len(g.slots), func(k int) bool { return !g.slots[k].Interval.Start.Before(start) })
+	return k, k < len(g.slots) && g.slots[k].Interval.Start.Equal(start)
+}
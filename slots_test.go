@@ -0,0 +1,66 @@
+package time_intervals
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SlotGrid(t *testing.T) {
+	// 9am -> 11am in 30 minute slots with 3 seats each
+	// slots:      |   |   |   |
+	// available:  AAAAAAAAAAAAAAAA
+	// blocked:        BBBBBB
+	//                     CCCCC
+	// capacity:   3   2   1   2
+
+	available := []Interval{testInterval(9*60, 11*60)}
+	blocked := []Interval{testInterval(9*60+30, 10*60+15), testInterval(10*60, 10*60+40)}
+	g := NewSlotGrid(available, 30, 3, blocked)
+
+	slots := g.Slots()
+	assert.Equal(t, 4, len(slots))
+	assert.Equal(t, []int{3, 2, 1, 2}, []int{slots[0].Capacity, slots[1].Capacity, slots[2].Capacity, slots[3].Capacity})
+
+	nine := slots[0].Interval.Start
+	assert.NoError(t, g.Book(nine, 2))
+	assert.Equal(t, ErrNotEnoughSeats, g.Book(nine, 2))
+	assert.NoError(t, g.Book(nine, 1))
+	assert.Equal(t, ErrSlotNotFound, g.Book(nine.Add(10*time.Minute), 1))
+	assert.Equal(t, ErrInvalidSeats, g.Book(slots[1].Interval.Start, 0))
+
+	free := g.SlotsWithSeats(2)
+	assert.Equal(t, 2, len(free))
+	assert.Equal(t, slots[1].Interval, free[0].Interval)
+	assert.Equal(t, slots[3].Interval, free[1].Interval)
+
+	assert.NoError(t, g.Cancel(nine, 2))
+	assert.Error(t, g.Cancel(nine, 2))
+	assert.Equal(t, 3, len(g.SlotsWithSeats(2)))
+}
+
+func Test_SlotGrid_ConcurrentBooking(t *testing.T) {
+	g := NewSlotGrid([]Interval{testInterval(0, 30)}, 30, 10, []Interval{})
+	start := g.Slots()[0].Interval.Start
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	booked := 0
+	for w := 0; w < 50; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if g.Book(start, 1) == nil {
+				mu.Lock()
+				booked++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, booked)
+	assert.Equal(t, 0, len(g.SlotsWithSeats(1)))
+}
@@ -0,0 +1,133 @@
+package time_intervals
+
+import "time"
+
+type SnapMode string
+
+const SnapRound SnapMode = "round"
+const SnapFloor SnapMode = "floor"
+const SnapCeil SnapMode = "ceil"
+const SnapExpand SnapMode = "expand" // grows the intervals outwards, e.g. for blocked intervals
+const SnapShrink SnapMode = "shrink" // shrinks the intervals inwards, e.g. for available intervals
+
+// FloorTime moves t back to the closest point of the wall clock grid of granularity g in loc.
+// The grid starts again at every local midnight, so 15 minute steps stay on :00, :15, :30 and :45 also on the days
+// when the clocks change. The result keeps the location of t.
+func FloorTime(t time.Time, g time.Duration, loc *time.Location) time.Time {
+	if g <= 0 {
+		return t
+	}
+	wall, y, m, d := wallClock(t, loc)
+	return wallTime(t, y, m, d, wall-wall%g, loc)
+}
+
+// CeilTime moves t forward to the closest point of the wall clock grid, see FloorTime
+func CeilTime(t time.Time, g time.Duration, loc *time.Location) time.Time {
+	wall, y, m, d := wallClock(t, loc)
+	if g <= 0 || wall%g == 0 {
+		return t
+	}
+	c := wall - wall%g + g
+	if c > 24*time.Hour {
+		c = 24 * time.Hour // the grid restarts at midnight
+	}
+	return wallTime(t, y, m, d, c, loc)
+}
+
+// RoundTime moves t to the closest point of the wall clock grid, halfway values go forward
+func RoundTime(t time.Time, g time.Duration, loc *time.Location) time.Time {
+	f, c := FloorTime(t, g, loc), CeilTime(t, g, loc)
+	if t.Sub(f) < c.Sub(t) {
+		return f
+	}
+	return c
+}
+
+func (i Interval) Floor(g time.Duration, loc *time.Location) Interval {
+	return Interval{Start: FloorTime(i.Start, g, loc), End: FloorTime(i.End, g, loc)}
+}
+
+func (i Interval) Ceil(g time.Duration, loc *time.Location) Interval {
+	return Interval{Start: CeilTime(i.Start, g, loc), End: CeilTime(i.End, g, loc)}
+}
+
+func (i Interval) Round(g time.Duration, loc *time.Location) Interval {
+	return Interval{Start: RoundTime(i.Start, g, loc), End: RoundTime(i.End, g, loc)}
+}
+
+// Expand returns the smallest interval on the grid that contains i
+func (i Interval) Expand(g time.Duration, loc *time.Location) Interval {
+	return Interval{Start: FloorTime(i.Start, g, loc), End: CeilTime(i.End, g, loc)}
+}
+
+// Shrink returns the largest interval on the grid inside i, its end is before its start when there is none
+func (i Interval) Shrink(g time.Duration, loc *time.Location) Interval {
+	return Interval{Start: CeilTime(i.Start, g, loc), End: FloorTime(i.End, g, loc)}
+}
+
+// Snap applies the mode to every interval and drops the ones that become empty.
+// The result is not merged, use MergeAndReturnNonOverlappingIntervals if expanded intervals may overlap.
+func Snap(a []Interval, mode SnapMode, g time.Duration, loc *time.Location) []Interval {
+	r := []Interval{}
+	for _, i := range a {
+		switch mode {
+		case SnapRound:
+			i = i.Round(g, loc)
+		case SnapFloor:
+			i = i.Floor(g, loc)
+		case SnapCeil:
+			i = i.Ceil(g, loc)
+		case SnapExpand:
+			i = i.Expand(g, loc)
+		case SnapShrink:
+			i = i.Shrink(g, loc)
+		}
+		if i.Start.Before(i.End) {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
+// returns the wall clock time elapsed since the local midnight and the local date of t
+func wallClock(t time.Time, loc *time.Location) (time.Duration, int, time.Month, int) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	l := t.In(loc)
+	y, m, d := l.Date()
+	wall := time.Duration(l.Hour())*time.Hour + time.Duration(l.Minute())*time.Minute +
+		time.Duration(l.Second())*time.Second + time.Duration(l.Nanosecond())
+	return wall, y, m, d
+}
+
+// builds the time at the given wall clock offset of the local date. When the wall clock time happens twice because
+// the clocks go back, the occurrence with the same offset as t is preferred. When it does not happen at all because
+// the clocks go forward, the instant of the change is returned, so FloorTime and CeilTime never pass over t.
+func wallTime(t time.Time, y int, m time.Month, d int, wall time.Duration, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	_, offset := t.In(loc).Zone()
+	c := time.Date(y, m, d, 0, 0, 0, int(wall), time.FixedZone("", offset)).In(loc)
+	if _, o := c.Zone(); o != offset {
+		c = time.Date(y, m, d, 0, 0, 0, int(wall), loc)
+	}
+
+	want := time.Date(y, m, d, 0, 0, 0, int(wall), time.UTC)
+	if !naiveWallClock(c).Equal(want) {
+		// in the gap, one of the bounds of the zone time.Date picked is the change
+		start, end := c.ZoneBounds()
+		for _, change := range []time.Time{start, end} {
+			if !change.IsZero() && naiveWallClock(change.Add(-time.Nanosecond)).Before(want) && naiveWallClock(change).After(want) {
+				c = change
+			}
+		}
+	}
+	return c.In(t.Location())
+}
+
+// the wall clock of t as the same date and time in UTC, to compare wall clocks across offsets
+func naiveWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
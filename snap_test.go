@@ -0,0 +1,136 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SnapTimes(t *testing.T) {
+	ts := time.Date(2018, 4, 10, 9, 3, 17, 442000000, time.UTC)
+
+	assert.Equal(t, time.Date(2018, 4, 10, 9, 0, 0, 0, time.UTC), FloorTime(ts, 5*time.Minute, time.UTC))
+	assert.Equal(t, time.Date(2018, 4, 10, 9, 5, 0, 0, time.UTC), CeilTime(ts, 5*time.Minute, time.UTC))
+	assert.Equal(t, time.Date(2018, 4, 10, 9, 5, 0, 0, time.UTC), RoundTime(ts, 5*time.Minute, time.UTC))
+	assert.Equal(t, time.Date(2018, 4, 10, 9, 0, 0, 0, time.UTC), RoundTime(ts, 15*time.Minute, time.UTC))
+
+	// already on the grid
+	onGrid := time.Date(2018, 4, 10, 9, 15, 0, 0, time.UTC)
+	assert.Equal(t, onGrid, FloorTime(onGrid, 15*time.Minute, time.UTC))
+	assert.Equal(t, onGrid, CeilTime(onGrid, 15*time.Minute, time.UTC))
+
+	// the grid restarts at midnight
+	late := time.Date(2018, 4, 10, 23, 50, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2018, 4, 11, 0, 0, 0, 0, time.UTC), CeilTime(late, 7*time.Hour, time.UTC))
+}
+
+func Test_SnapTimes_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// the clocks go back from 02:00 EDT to 01:00 EST on 2018-11-04, 01:20 happens twice
+	secondOneTwenty := time.Date(2018, 11, 4, 6, 20, 0, 0, time.UTC).In(ny)
+	assert.Equal(t, 1, secondOneTwenty.Hour())
+
+	c := CeilTime(secondOneTwenty, 15*time.Minute, ny)
+	assert.Equal(t, 10*time.Minute, c.Sub(secondOneTwenty))
+	f := FloorTime(secondOneTwenty, 15*time.Minute, ny)
+	assert.Equal(t, 5*time.Minute, secondOneTwenty.Sub(f))
+
+	// after the change the wall clock grid is still aligned, in UTC the points moved by an hour
+	before := time.Date(2018, 11, 3, 10, 7, 0, 0, ny)
+	after := time.Date(2018, 11, 5, 10, 7, 0, 0, ny)
+	assert.Equal(t, 0, FloorTime(before, 30*time.Minute, ny).Minute())
+	assert.Equal(t, 0, FloorTime(after, 30*time.Minute, ny).Minute())
+	assert.Equal(t, 10, FloorTime(after, 30*time.Minute, ny).Hour())
+
+	// 90 minute slots count from local midnight, not from a UTC instant
+	assert.Equal(t, time.Date(2018, 11, 5, 9, 0, 0, 0, ny), FloorTime(after, 90*time.Minute, ny))
+}
+
+func Test_SnapTimes_DST_Gap(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// the clocks go forward from 02:00 EST to 03:00 EDT on 2018-03-11, the grid points inside the gap become the
+	// instant of the change
+	change := time.Date(2018, 3, 11, 7, 0, 0, 0, time.UTC).In(ny)
+	oneFiftyEight := time.Date(2018, 3, 11, 1, 58, 0, 0, ny)
+	assert.True(t, change.Equal(CeilTime(oneFiftyEight, 5*time.Minute, ny)))
+	assert.True(t, change.Equal(RoundTime(oneFiftyEight, 5*time.Minute, ny)))
+	assert.True(t, change.Equal(FloorTime(change.Add(time.Minute), 45*time.Minute, ny)), "02:15 does not exist")
+
+	// blocked time is never lost and available time never grows
+	blocked := Interval{Start: time.Date(2018, 3, 11, 1, 40, 0, 0, ny), End: oneFiftyEight}
+	assertSameIntervals(t, []Interval{{Start: blocked.Start, End: change}}, Snap([]Interval{blocked}, SnapExpand, 5*time.Minute, ny))
+	available := Interval{Start: time.Date(2018, 3, 11, 1, 50, 0, 0, ny), End: time.Date(2018, 3, 11, 4, 0, 0, 0, ny)}
+	assertSameIntervals(t, []Interval{{Start: change, End: available.End}}, Snap([]Interval{available}, SnapShrink, 15*time.Minute, ny))
+
+	// Lord Howe Island moves its clocks by 30 minutes, from 02:00 to 02:30 on 2018-10-07
+	lordHowe, err := time.LoadLocation("Australia/Lord_Howe")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	twoThirtyTwo := time.Date(2018, 10, 7, 2, 32, 0, 0, lordHowe)
+	f := FloorTime(twoThirtyTwo, 7*time.Minute, lordHowe)
+	assert.Equal(t, "02:30", f.Format("15:04"))
+	assert.False(t, f.After(twoThirtyTwo))
+}
+
+func Test_SnapTimes_AroundTime(t *testing.T) {
+	// Floor(t) <= t <= Ceil(t) on every day with a clock change
+	for _, name := range []string{"America/New_York", "Europe/Berlin", "Australia/Lord_Howe", "America/Sao_Paulo"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skip("time zone database not available")
+		}
+		for _, day := range []time.Time{
+			time.Date(2018, 3, 10, 12, 0, 0, 0, time.UTC), time.Date(2018, 3, 24, 12, 0, 0, 0, time.UTC),
+			time.Date(2018, 10, 6, 12, 0, 0, 0, time.UTC), time.Date(2018, 10, 27, 12, 0, 0, 0, time.UTC),
+			time.Date(2018, 11, 3, 12, 0, 0, 0, time.UTC), time.Date(2018, 2, 17, 0, 0, 0, 0, time.UTC),
+		} {
+			for tt := day; tt.Before(day.Add(36 * time.Hour)); tt = tt.Add(7 * time.Minute) {
+				for _, g := range []time.Duration{5 * time.Minute, 7 * time.Minute, 45 * time.Minute, 3 * time.Hour} {
+					f, c := FloorTime(tt, g, loc), CeilTime(tt, g, loc)
+					if !assert.False(t, f.After(tt), "%s floor %v of %v", name, f.In(loc), tt.In(loc)) ||
+						!assert.False(t, c.Before(tt), "%s ceil %v of %v", name, c.In(loc), tt.In(loc)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_Snap(t *testing.T) {
+	// available:  ..[AAAAAAA]..   shrink -> inside the grid
+	// blocked:    ..[BB]......    expand -> covering the grid cells
+
+	minute := func(m int, s int) time.Time {
+		return time.Date(2018, 4, 7, 0, m, s, 0, time.UTC)
+	}
+	available := []Interval{{Start: minute(3, 17), End: minute(41, 2)}, {Start: minute(50, 0), End: minute(58, 0)}}
+	blocked := []Interval{{Start: minute(16, 0), End: minute(19, 30)}}
+
+	shrunk := Snap(available, SnapShrink, 15*time.Minute, time.UTC)
+	assert.Equal(t, 1, len(shrunk), "the second interval does not contain a full grid cell: %v", shrunk)
+	assert.Equal(t, "", intervalsDiff(testInterval(15, 30), shrunk[0]))
+
+	expanded := Snap(blocked, SnapExpand, 15*time.Minute, time.UTC)
+	assert.Equal(t, "", intervalsDiff(testInterval(15, 30), expanded[0]))
+
+	assert.Equal(t, 0, len(SubstractBlockedIntervals(shrunk, expanded)))
+
+	rounded := Snap(available, SnapRound, 15*time.Minute, time.UTC)
+	assert.Equal(t, 2, len(rounded))
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 45), rounded[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(45, 60), rounded[1]))
+
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 30), available[0].Floor(15*time.Minute, time.UTC)))
+	assert.Equal(t, "", intervalsDiff(testInterval(15, 45), available[0].Ceil(15*time.Minute, time.UTC)))
+}
@@ -0,0 +1,108 @@
+package time_intervals
+
+// IsSortedDisjoint reports if the intervals are not empty, ordered by start and separated from each other by a gap,
+// which is the shape of the results of SubstractBlockedIntervals. Such inputs are handled in linear time.
+func IsSortedDisjoint(a []Interval) bool {
+	for k, i := range a {
+		if !i.Start.Before(i.End) {
+			return false
+		}
+		if k > 0 && !a[k-1].End.Before(i.Start) {
+			return false
+		}
+	}
+	return true
+}
+
+// SubstractSortedDisjoint is SubstractBlockedIntervals for inputs that pass IsSortedDisjoint, using two pointers in O(n)
+func SubstractSortedDisjoint(available []Interval, blocked []Interval) []Interval {
+	r := []Interval{}
+	j := 0
+	for _, a := range available {
+		for j < len(blocked) && !blocked[j].End.After(a.Start) {
+			j++
+		}
+		current := a.Start
+		// a blocked interval can stretch over several available ones, so j is not moved past it here
+		for k := j; k < len(blocked) && blocked[k].Start.Before(a.End); k++ {
+			if blocked[k].Start.After(current) {
+				r = append(r, Interval{Start: current, End: blocked[k].Start})
+			}
+			if blocked[k].End.After(current) {
+				current = blocked[k].End
+			}
+		}
+		if current.Before(a.End) {
+			r = append(r, Interval{Start: current, End: a.End})
+		}
+	}
+	return r
+}
+
+// IntersectSortedDisjoint returns the time covered by both inputs, which must pass IsSortedDisjoint
+func IntersectSortedDisjoint(a []Interval, b []Interval) []Interval {
+	r := []Interval{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		s, e := a[i].Start, a[i].End
+		if b[j].Start.After(s) {
+			s = b[j].Start
+		}
+		if b[j].End.Before(e) {
+			e = b[j].End
+		}
+		if s.Before(e) {
+			r = append(r, Interval{Start: s, End: e})
+		}
+		if b[j].End.Before(a[i].End) {
+			j++
+		} else {
+			i++
+		}
+	}
+	return r
+}
+
+// UnionSortedDisjoint returns the time covered by any of the inputs, which must pass IsSortedDisjoint.
+// Touching intervals are merged, like in MergeAndReturnNonOverlappingIntervals.
+func UnionSortedDisjoint(a []Interval, b []Interval) []Interval {
+	r := []Interval{}
+	add := func(x Interval) {
+		if n := len(r); n > 0 && !x.Start.After(r[n-1].End) {
+			if x.End.After(r[n-1].End) {
+				r[n-1].End = x.End
+			}
+			return
+		}
+		r = append(r, x)
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if j == len(b) || (i < len(a) && a[i].Start.Before(b[j].Start)) {
+			add(a[i])
+			i++
+		} else {
+			add(b[j])
+			j++
+		}
+	}
+	return r
+}
+
+// Intersect returns the ordered disjoint intervals covered by both a and b
+func Intersect(a []Interval, b []Interval) []Interval {
+	if !IsSortedDisjoint(a) {
+		a = MergeAndReturnNonOverlappingIntervals(a)
+	}
+	if !IsSortedDisjoint(b) {
+		b = MergeAndReturnNonOverlappingIntervals(b)
+	}
+	return IntersectSortedDisjoint(a, b)
+}
+
+// Union returns the ordered disjoint intervals covered by a or b
+func Union(a []Interval, b []Interval) []Interval {
+	if IsSortedDisjoint(a) && IsSortedDisjoint(b) {
+		return UnionSortedDisjoint(a, b)
+	}
+	return MergeAndReturnNonOverlappingIntervals(append(append([]Interval{}, a...), b...))
+}
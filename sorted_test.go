@@ -0,0 +1,80 @@
+package time_intervals
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsSortedDisjoint(t *testing.T) {
+	assert.True(t, IsSortedDisjoint([]Interval{}))
+	assert.True(t, IsSortedDisjoint([]Interval{testInterval(1, 2), testInterval(3, 5)}))
+	assert.False(t, IsSortedDisjoint([]Interval{testInterval(3, 5), testInterval(1, 2)}), "not sorted")
+	assert.False(t, IsSortedDisjoint([]Interval{testInterval(1, 3), testInterval(2, 5)}), "overlapping")
+	assert.False(t, IsSortedDisjoint([]Interval{testInterval(1, 2), testInterval(2, 5)}), "touching")
+	assert.False(t, IsSortedDisjoint([]Interval{testInterval(1, 1)}), "empty")
+}
+
+func Test_SortedDisjointOperations(t *testing.T) {
+	// a:     AAAA   AAAAAAA   AA
+	// b:       BBBBBBB  B   BBBBBB
+	// a - b: AA       A A
+	// a & b:   BB   BB  B     BB
+	// a | b: UUUUUUUUUUUUUU UUUUUUUU
+
+	a := []Interval{testInterval(0, 4), testInterval(7, 14), testInterval(17, 19)}
+	b := []Interval{testInterval(2, 9), testInterval(11, 12), testInterval(15, 21)}
+
+	r := SubstractSortedDisjoint(a, b)
+	assert.Equal(t, 3, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 2), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(9, 11), r[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(12, 14), r[2]))
+
+	r = IntersectSortedDisjoint(a, b)
+	assert.Equal(t, 4, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(2, 4), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(7, 9), r[1]))
+	assert.Equal(t, "", intervalsDiff(testInterval(11, 12), r[2]))
+	assert.Equal(t, "", intervalsDiff(testInterval(17, 19), r[3]))
+
+	r = UnionSortedDisjoint(a, b)
+	assert.Equal(t, 2, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 14), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(15, 21), r[1]))
+}
+
+func Test_SortedDisjoint_MatchesSweep(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for run := 0; run < 1000; run++ {
+		// the merged random intervals are sorted and disjoint
+		a := MergeAndReturnNonOverlappingIntervals(randomGridIntervals(rnd, rnd.Intn(8)))
+		b := MergeAndReturnNonOverlappingIntervals(randomGridIntervals(rnd, rnd.Intn(8)))
+		assert.True(t, IsSortedDisjoint(a))
+
+		assertSameIntervals(t, AppendSubstractBlockedIntervals([]Interval{}, a, b), SubstractSortedDisjoint(a, b))
+		assertSameIntervals(t, minutesToIntervals(intervalsToMinutes(append(a, b...))), UnionSortedDisjoint(a, b))
+
+		both := make([]bool, propertyGridMinutes)
+		am, bm := intervalsToMinutes(a), intervalsToMinutes(b)
+		for m := range both {
+			both[m] = am[m] && bm[m]
+		}
+		assertSameIntervals(t, minutesToIntervals(both), IntersectSortedDisjoint(a, b))
+	}
+}
+
+func Test_IntersectAndUnion_UnsortedInput(t *testing.T) {
+	a := []Interval{testInterval(7, 14), testInterval(0, 4), testInterval(2, 5)}
+	b := []Interval{testInterval(3, 8)}
+
+	r := Intersect(a, b)
+	assert.Equal(t, 2, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(3, 5), r[0]))
+	assert.Equal(t, "", intervalsDiff(testInterval(7, 8), r[1]))
+
+	r = Union(a, b)
+	assert.Equal(t, 1, len(r), "result: %v", r)
+	assert.Equal(t, "", intervalsDiff(testInterval(0, 14), r[0]))
+}
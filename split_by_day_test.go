@@ -115,14 +115,21 @@ func testDHInterval(dStart, hStart, dEnd, hEnd int) Interval {
 
 func intervalsDiff(a, b Interval) string {
 	if a.Start != b.Start {
-		return fmt.Sprintf("start time not equal: %v <> %v", a.Start, b.Start)
+		return fmt.Sprintf("start time not equal: %v <> %v\n%s", a.Start, b.Start, renderIntervalsDiff(a, b))
 	}
 	if a.End != b.End {
-		return fmt.Sprintf("end time not equal: %v <> %v", a.End, b.End)
+		return fmt.Sprintf("end time not equal: %v <> %v\n%s", a.End, b.End, renderIntervalsDiff(a, b))
 	}
 	return ""
 }
 
+func renderIntervalsDiff(expected, actual Interval) string {
+	return RenderTimeline([]TimelineRow{
+		{Label: "expected", Intervals: []Interval{expected}},
+		{Label: "actual", Intervals: []Interval{actual}},
+	}, TimelineOptions{LabelLayout: "02 15:04"})
+}
+
 func getKeys(m map[time.Time][]Interval) []time.Time {
 	res := []time.Time{}
 	for k, _ := range m {
@@ -0,0 +1,69 @@
+package time_intervals
+
+import "time"
+
+// IntervalStats describes how much of a window is covered by a set of intervals
+type IntervalStats struct {
+	Window       Interval
+	Total        time.Duration // length of the window
+	Covered      time.Duration
+	Utilization  float64 // Covered / Total
+	Intervals    int     // number of intervals left after merging and clipping to the window
+	MeanInterval time.Duration
+	Gaps         int // uncovered parts of the window, including the ones at its edges
+	LongestGap   time.Duration
+	ShortestGap  time.Duration
+}
+
+// Stats merges the intervals, clips them to the window and reports the coverage of the window
+func Stats(a []Interval, window Interval) IntervalStats {
+	s := IntervalStats{Window: window}
+	if !window.Start.Before(window.End) {
+		return s
+	}
+	s.Total = window.End.Sub(window.Start)
+
+	covered := clipIntervals(MergeAndReturnNonOverlappingIntervals(a), window)
+	for _, i := range covered {
+		s.Covered += i.End.Sub(i.Start)
+	}
+	s.Intervals = len(covered)
+	if s.Intervals > 0 {
+		s.MeanInterval = s.Covered / time.Duration(s.Intervals)
+	}
+	s.Utilization = float64(s.Covered) / float64(s.Total)
+
+	gaps := SubstractBlockedIntervals([]Interval{window}, covered)
+	s.Gaps = len(gaps)
+	for k, g := range gaps {
+		d := g.End.Sub(g.Start)
+		if d > s.LongestGap {
+			s.LongestGap = d
+		}
+		if k == 0 || d < s.ShortestGap {
+			s.ShortestGap = d
+		}
+	}
+	return s
+}
+
+type DayStats struct {
+	Date            time.Time
+	CountSinceFirst int
+	Stats           IntervalStats
+}
+
+// StatsForEachDay computes the Stats of every day returned by IntervalsForEachDayInRange.
+// The window of a day ends one millisecond before midnight, the same way IntervalsByDay splits the intervals.
+func StatsForEachDay(days []DayIntervals) []DayStats {
+	r := []DayStats{}
+	for _, d := range days {
+		window := Interval{Start: d.Date, End: d.Date.AddDate(0, 0, 1).Add(-1 * time.Millisecond)}
+		r = append(r, DayStats{
+			Date:            d.Date,
+			CountSinceFirst: d.CountSinceFirst,
+			Stats:           Stats(d.OrderedDisjunctIntervals, window),
+		})
+	}
+	return r
+}
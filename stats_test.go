@@ -0,0 +1,57 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Stats(t *testing.T) {
+	// window:  WWWWWWWWWWWWWWWWWWWW
+	// covered:   AAAA  BBCC     DDDDDDD
+	// gaps:    --    --    -----
+
+	a := []Interval{testInterval(2, 6), testInterval(8, 10), testInterval(9, 12), testInterval(17, 24)}
+	s := Stats(a, testInterval(0, 20))
+
+	assert.Equal(t, 20*time.Minute, s.Total)
+	assert.Equal(t, 11*time.Minute, s.Covered)
+	assert.InDelta(t, 0.55, s.Utilization, 0.0001)
+	assert.Equal(t, 3, s.Intervals)
+	assert.Equal(t, 11*time.Minute/3, s.MeanInterval)
+	assert.Equal(t, 3, s.Gaps)
+	assert.Equal(t, 5*time.Minute, s.LongestGap)
+	assert.Equal(t, 2*time.Minute, s.ShortestGap)
+}
+
+func Test_Stats_Empty(t *testing.T) {
+	s := Stats([]Interval{}, testInterval(0, 20))
+	assert.Equal(t, time.Duration(0), s.Covered)
+	assert.Equal(t, 0.0, s.Utilization)
+	assert.Equal(t, 1, s.Gaps)
+	assert.Equal(t, 20*time.Minute, s.LongestGap)
+}
+
+func Test_StatsForEachDay(t *testing.T) {
+	// day0     day1	day2
+	// |		|		|
+	//       AAAAAAAAAAAAAA
+
+	A := testDHInterval(0, 12, 2, 6)
+	days, err := IntervalsForEachDayInRange([]Interval{A}, A.Start, A.End)
+	assert.NoError(t, err)
+
+	s := StatsForEachDay(days)
+	assert.Equal(t, 3, len(s))
+
+	assert.Equal(t, 12*time.Hour-time.Millisecond, s[0].Stats.Covered)
+	assert.Equal(t, 1, s[0].Stats.Gaps)
+
+	assert.Equal(t, 1, s[1].CountSinceFirst)
+	assert.Equal(t, 1.0, s[1].Stats.Utilization)
+	assert.Equal(t, 0, s[1].Stats.Gaps)
+
+	assert.Equal(t, 6*time.Hour, s[2].Stats.Covered)
+	assert.Equal(t, 18*time.Hour-time.Millisecond, s[2].Stats.LongestGap)
+}
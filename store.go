@@ -0,0 +1,116 @@
+package time_intervals
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IntervalStore persists the available and blocked intervals of many resources in one table through database/sql.
+// The times are stored as Unix nanoseconds in BIGINT columns, so the overlap filter is a plain integer comparison on
+// every database, and read back in UTC. CreateTable adds an index on (resource_id, interval_type, start_ns) so the
+// overlap queries do not scan the whole table.
+type IntervalStore struct {
+	db    *sql.DB
+	table string
+
+	// Placeholder returns the bind parameter for the n-th argument (starting at 1), "?" by default.
+	// Use func(n int) string { return fmt.Sprintf("$%d", n) } for PostgreSQL.
+	Placeholder func(n int) string
+}
+
+func NewIntervalStore(db *sql.DB, table string) (*IntervalStore, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, errors.New(fmt.Sprintf("Invalid table name %q", table))
+	}
+	return &IntervalStore{db: db, table: table}, nil
+}
+
+// CreateTable creates the table and the index on (resource_id, interval_type, start_ns) used by Overlapping.
+// CREATE INDEX IF NOT EXISTS works on SQLite and PostgreSQL, on MySQL create the table and the index yourself.
+func (s *IntervalStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	resource_id VARCHAR(255) NOT NULL,
+	interval_type VARCHAR(16) NOT NULL,
+	start_ns BIGINT NOT NULL,
+	end_ns BIGINT NOT NULL
+)`, s.table))
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_resource_start ON %s (resource_id, interval_type, start_ns)", s.table, s.table))
+	return err
+}
+
+// Insert stores the intervals of a resource in a single transaction
+func (s *IntervalStore) Insert(ctx context.Context, resourceID string, t IntervalType, intervals ...Interval) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (resource_id, interval_type, start_ns, end_ns) VALUES (%s, %s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4))
+	for _, i := range intervals {
+		if i.End.Before(i.Start) {
+			tx.Rollback()
+			return ErrInvalidInterval
+		}
+		if _, err := tx.ExecContext(ctx, query, resourceID, string(t), i.Start.UnixNano(), i.End.UnixNano()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Overlapping returns the intervals of the given type overlapping the window, ordered by start. The overlap filter
+// runs in the database, the intervals are not clipped to the window.
+func (s *IntervalStore) Overlapping(ctx context.Context, resourceID string, t IntervalType, window Interval) ([]Interval, error) {
+	query := fmt.Sprintf("SELECT start_ns, end_ns FROM %s WHERE resource_id = %s AND interval_type = %s AND start_ns < %s AND end_ns > %s ORDER BY start_ns",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4))
+	rows, err := s.db.QueryContext(ctx, query, resourceID, string(t), window.End.UnixNano(), window.Start.UnixNano())
+	if err != nil {
+		return []Interval{}, err
+	}
+	defer rows.Close()
+
+	r := []Interval{}
+	for rows.Next() {
+		var start, end int64
+		if err := rows.Scan(&start, &end); err != nil {
+			return []Interval{}, err
+		}
+		r = append(r, Interval{Start: time.Unix(0, start).UTC(), End: time.Unix(0, end).UTC()})
+	}
+	if err := rows.Err(); err != nil {
+		return []Interval{}, err
+	}
+	return r, nil
+}
+
+// Load returns the available and blocked intervals of a resource overlapping the window, ready for
+// SubstractBlockedIntervals
+func (s *IntervalStore) Load(ctx context.Context, resourceID string, window Interval) (available []Interval, blocked []Interval, err error) {
+	available, err = s.Overlapping(ctx, resourceID, Available, window)
+	if err != nil {
+		return []Interval{}, []Interval{}, err
+	}
+	blocked, err = s.Overlapping(ctx, resourceID, Blocked, window)
+	if err != nil {
+		return []Interval{}, []Interval{}, err
+	}
+	return available, blocked, nil
+}
+
+func (s *IntervalStore) placeholder(n int) string {
+	if s.Placeholder == nil {
+		return "?"
+	}
+	return s.Placeholder(n)
+}
@@ -0,0 +1,133 @@
+package time_intervals
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// an in memory database/sql driver that understands only the statements of IntervalStore
+type fakeIntervalsDriver struct {
+	mu      sync.Mutex
+	rows    [][]driver.Value // resource_id, interval_type, start_ns, end_ns
+	queries []string
+}
+
+type fakeIntervalsConn struct{ d *fakeIntervalsDriver }
+type fakeIntervalsStmt struct {
+	d     *fakeIntervalsDriver
+	query string
+}
+type fakeIntervalsRows struct{ rows [][]driver.Value }
+
+func (d *fakeIntervalsDriver) Open(name string) (driver.Conn, error) {
+	return fakeIntervalsConn{d}, nil
+}
+
+func (c fakeIntervalsConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.queries = append(c.d.queries, query)
+	return fakeIntervalsStmt{c.d, query}, nil
+}
+func (c fakeIntervalsConn) Close() error              { return nil }
+func (c fakeIntervalsConn) Begin() (driver.Tx, error) { return c, nil }
+func (c fakeIntervalsConn) Commit() error             { return nil }
+func (c fakeIntervalsConn) Rollback() error           { return nil }
+
+func (s fakeIntervalsStmt) Close() error  { return nil }
+func (s fakeIntervalsStmt) NumInput() int { return -1 }
+
+func (s fakeIntervalsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if strings.HasPrefix(s.query, "INSERT") {
+		s.d.rows = append(s.d.rows, args)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeIntervalsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	// WHERE resource_id = ? AND interval_type = ? AND start_ns < ? AND end_ns > ?
+	r := [][]driver.Value{}
+	for _, row := range s.d.rows {
+		if row[0] == args[0] && row[1] == args[1] && row[2].(int64) < args[2].(int64) && row[3].(int64) > args[3].(int64) {
+			r = append(r, []driver.Value{row[2], row[3]})
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i][0].(int64) < r[j][0].(int64) })
+	return &fakeIntervalsRows{r}, nil
+}
+
+func (r *fakeIntervalsRows) Columns() []string { return []string{"start_ns", "end_ns"} }
+func (r *fakeIntervalsRows) Close() error      { return nil }
+func (r *fakeIntervalsRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var fakeDriver = &fakeIntervalsDriver{}
+
+func init() {
+	sql.Register("fake_intervals", fakeDriver)
+}
+
+func Test_IntervalStore(t *testing.T) {
+	db, err := sql.Open("fake_intervals", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewIntervalStore(db, "intervals; DROP TABLE users")
+	assert.Error(t, err)
+
+	s, err := NewIntervalStore(db, "intervals")
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.NoError(t, s.CreateTable(ctx))
+	assert.Contains(t, fakeDriver.queries, "CREATE INDEX IF NOT EXISTS intervals_resource_start ON intervals (resource_id, interval_type, start_ns)")
+
+	// window:       WWWWWWWWWW
+	// available: AAAAAA  AAAA   AAA
+	// blocked:     BB  BB    BB
+	assert.NoError(t, s.Insert(ctx, "room-1", Available, testInterval(0, 6), testInterval(8, 12), testInterval(15, 18)))
+	assert.NoError(t, s.Insert(ctx, "room-1", Blocked, testInterval(2, 4), testInterval(6, 8), testInterval(12, 14)))
+	assert.NoError(t, s.Insert(ctx, "room-2", Available, testInterval(0, 20)))
+
+	available, blocked, err := s.Load(ctx, "room-1", testInterval(3, 13))
+	assert.NoError(t, err)
+	assertSameIntervals(t, []Interval{testInterval(0, 6), testInterval(8, 12)}, available)
+	assertSameIntervals(t, []Interval{testInterval(2, 4), testInterval(6, 8), testInterval(12, 14)}, blocked)
+
+	free := clipIntervals(SubstractBlockedIntervals(available, blocked), testInterval(3, 13))
+	assertSameIntervals(t, []Interval{testInterval(4, 6), testInterval(8, 12)}, free)
+
+	assert.Contains(t, fakeDriver.queries, "SELECT start_ns, end_ns FROM intervals WHERE resource_id = ? AND interval_type = ? AND start_ns < ? AND end_ns > ? ORDER BY start_ns")
+}
+
+func Test_IntervalStore_Placeholders(t *testing.T) {
+	db, err := sql.Open("fake_intervals", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	s, err := NewIntervalStore(db, "pg_intervals")
+	assert.NoError(t, err)
+	s.Placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+
+	_, err = s.Overlapping(context.Background(), "room-1", Available, testInterval(0, 10))
+	assert.NoError(t, err)
+	assert.Contains(t, fakeDriver.queries, "SELECT start_ns, end_ns FROM pg_intervals WHERE resource_id = $1 AND interval_type = $2 AND start_ns < $3 AND end_ns > $4 ORDER BY start_ns")
+}
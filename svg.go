@@ -0,0 +1,121 @@
+package time_intervals
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"time"
+)
+
+type SVGOptions struct {
+	Width       int           // width of the whole chart in pixels, defaults to 800
+	RowHeight   int           // defaults to 24
+	LabelWidth  int           // space reserved for the row labels, defaults to 120
+	Start       time.Time     // start of the axis, defaults to the earliest start of all rows
+	End         time.Time     // end of the axis, defaults to the latest end of all rows
+	TickEvery   time.Duration // distance between two axis ticks, picked from the axis length by default
+	LabelLayout string        // time layout of the tick labels, defaults to "15:04"
+	Colors      []string      // fill colors used for the rows in order, cycled if there are more rows
+}
+
+var defaultSVGColors = []string{"#4caf50", "#e53935", "#1e88e5", "#fb8c00", "#8e24aa", "#00897b"}
+
+var svgTickSteps = []time.Duration{
+	time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// RenderSVG writes a self contained Gantt style SVG chart with one bar row per TimelineRow (for example available,
+// blocked and the result of SubstractBlockedIntervals) on a shared time axis. It uses no external fonts or styles.
+func RenderSVG(w io.Writer, rows []TimelineRow, opts SVGOptions) error {
+	start, end := opts.Start, opts.End
+	for _, r := range rows {
+		for _, i := range r.Intervals {
+			if opts.Start.IsZero() && (start.IsZero() || i.Start.Before(start)) {
+				start = i.Start
+			}
+			if opts.End.IsZero() && (end.IsZero() || i.End.After(end)) {
+				end = i.End
+			}
+		}
+	}
+	if !start.Before(end) {
+		end = start.Add(time.Hour)
+	}
+	if opts.Width <= 0 {
+		opts.Width = 800
+	}
+	if opts.RowHeight <= 0 {
+		opts.RowHeight = 24
+	}
+	if opts.LabelWidth <= 0 {
+		opts.LabelWidth = 120
+	}
+	if opts.LabelLayout == "" {
+		opts.LabelLayout = "15:04"
+	}
+	if len(opts.Colors) == 0 {
+		opts.Colors = defaultSVGColors
+	}
+	if opts.TickEvery <= 0 {
+		opts.TickEvery = svgTickSteps[len(svgTickSteps)-1]
+		for _, s := range svgTickSteps {
+			if end.Sub(start)/s <= 12 {
+				opts.TickEvery = s
+				break
+			}
+		}
+	}
+
+	const margin = 10
+	const axisHeight = 30
+	plotWidth := float64(opts.Width - opts.LabelWidth - 2*margin)
+	height := len(rows)*opts.RowHeight + axisHeight + 2*margin
+	x := func(t time.Time) float64 {
+		return float64(opts.LabelWidth+margin) + plotWidth*float64(t.Sub(start))/float64(end.Sub(start))
+	}
+
+	b := bytes.Buffer{}
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", opts.Width, height, opts.Width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", opts.Width, height)
+
+	for k, r := range rows {
+		y := margin + k*opts.RowHeight
+		color := opts.Colors[k%len(opts.Colors)]
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", margin, y+opts.RowHeight/2, html.EscapeString(r.Label))
+		for _, i := range clipIntervals(MergeAndReturnNonOverlappingIntervals(r.Intervals), Interval{Start: start, End: end}) {
+			fmt.Fprintf(&b, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="%s"><title>%s - %s</title></rect>`+"\n",
+				x(i.Start), y+2, x(i.End)-x(i.Start), opts.RowHeight-4, html.EscapeString(color),
+				html.EscapeString(i.Start.Format(opts.LabelLayout)), html.EscapeString(i.End.Format(opts.LabelLayout)))
+		}
+	}
+
+	axisY := margin + len(rows)*opts.RowHeight
+	fmt.Fprintf(&b, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%d" stroke="#333333"/>`+"\n", x(start), axisY, x(end), axisY)
+	// the ticks follow the wall clock of the axis start, so they are on local hours also across DST changes
+	loc := start.Location()
+	for t := CeilTime(start, opts.TickEvery, loc); !t.After(end); t = CeilTime(t.Add(time.Nanosecond), opts.TickEvery, loc) {
+		fmt.Fprintf(&b, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%d" stroke="#cccccc"/>`+"\n", x(t), margin, x(t), axisY+4)
+		fmt.Fprintf(&b, `<text x="%.2f" y="%d" text-anchor="middle">%s</text>`+"\n", x(t), axisY+18, html.EscapeString(t.Format(opts.LabelLayout)))
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// DayRows turns the result of IntervalsForEachDayInRange into one row per day labeled with its date.
+// The intervals of every day are moved onto the date of the first day so that all the rows share a 24 hour axis.
+func DayRows(days []DayIntervals) []TimelineRow {
+	rows := []TimelineRow{}
+	for _, d := range days {
+		shift := days[0].Date.Sub(d.Date)
+		r := TimelineRow{Label: d.Date.Format("2006-01-02"), Intervals: []Interval{}}
+		for _, i := range d.OrderedDisjunctIntervals {
+			r.Intervals = append(r.Intervals, Interval{Start: i.Start.Add(shift), End: i.End.Add(shift)})
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
@@ -0,0 +1,95 @@
+package time_intervals
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		assert.NoError(t, os.WriteFile(path, actual, 0644))
+	}
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func Test_RenderSVG(t *testing.T) {
+	available := []Interval{testInterval(9*60, 12*60), testInterval(13*60, 17*60)}
+	blocked := []Interval{testInterval(10*60, 10*60+30), testInterval(15*60, 16*60)}
+
+	b := bytes.Buffer{}
+	err := RenderSVG(&b, []TimelineRow{
+		{Label: "available", Intervals: available},
+		{Label: "blocked", Intervals: blocked},
+		{Label: "result <free>", Intervals: SubstractBlockedIntervals(available, blocked)},
+	}, SVGOptions{})
+	assert.NoError(t, err)
+	assertGolden(t, "gantt.svg", b.Bytes())
+}
+
+func Test_RenderSVG_DayRows(t *testing.T) {
+	// day0     day1	day2
+	// |		|		|
+	//   AAA   BBB  C
+
+	A := testDHInterval(0, 3, 0, 7)
+	B := testDHInterval(0, 17, 1, 5)
+	C := testDHInterval(1, 11, 1, 13)
+
+	days, err := IntervalsForEachDayInRange([]Interval{A, B, C}, A.Start, C.End)
+	assert.NoError(t, err)
+
+	rows := DayRows(days)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "2018-04-11", rows[1].Label)
+	assert.Equal(t, "", intervalsDiff(testDHInterval(0, 11, 0, 13), rows[1].Intervals[1]))
+
+	b := bytes.Buffer{}
+	err = RenderSVG(&b, rows, SVGOptions{Start: baseTime, End: baseTime.AddDate(0, 0, 1), Width: 600})
+	assert.NoError(t, err)
+	assertGolden(t, "gantt_days.svg", b.Bytes())
+}
+
+func Test_RenderSVG_LocalTicks(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	day := func(h, m int) time.Time { return time.Date(2018, 4, 10, h, m, 0, 0, kolkata) }
+
+	b := bytes.Buffer{}
+	err = RenderSVG(&b, []TimelineRow{
+		{Label: "support", Intervals: []Interval{{Start: day(9, 0), End: day(13, 0)}, {Start: day(14, 0), End: day(17, 0)}}},
+		{Label: "tickets", Intervals: []Interval{{Start: day(10, 15), End: day(11, 45)}}},
+	}, SVGOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), ">09:00</text>")
+	assert.NotContains(t, b.String(), ">09:30</text>", "ticks are on local hours, not on UTC ones")
+	assertGolden(t, "gantt_kolkata.svg", b.Bytes())
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// two days over the DST change, 6 hour ticks stay on 00:00, 06:00, 12:00 and 18:00
+	start := time.Date(2018, 3, 10, 0, 0, 0, 0, ny)
+	b.Reset()
+	err = RenderSVG(&b, []TimelineRow{{Label: "on call", Intervals: []Interval{{Start: start, End: start.AddDate(0, 0, 2)}}}}, SVGOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(b.String(), ">00:00</text>"), "both ends of the axis")
+	for _, label := range []string{"06:00", "12:00", "18:00"} {
+		assert.Equal(t, 2, strings.Count(b.String(), ">"+label+"</text>"), label)
+	}
+	assert.NotContains(t, b.String(), ">02:00</text>")
+}
@@ -0,0 +1,152 @@
+package time_intervals
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Sweeper runs the same sweep as SubstractBlockedIntervals, but instead of pushing every endpoint through the heap
+// it fills a preallocated endpoints slice and sorts it once. When the starts and the ends of both inputs are already
+// in order the endpoints are merged in O(n) instead of sorted. The endpoints slice is kept between calls, so reusing
+// a Sweeper together with a destination slice makes repeated calls allocation free. A Sweeper is not safe for
+// concurrent use.
+type Sweeper struct {
+	endpoints []Endpoint
+}
+
+// AppendSubstractBlockedIntervals appends the result of SubstractBlockedIntervals(available, blocked) to dst.
+// Pass dst[:0] to reuse the memory of a previous result.
+func (s *Sweeper) AppendSubstractBlockedIntervals(dst []Interval, available []Interval, blocked []Interval) []Interval {
+	return sweepEndpoints(dst, s.orderedEndpoints(available, blocked))
+}
+
+// fills the reused endpoints slice with the endpoints of both inputs in sweep order. The sort can not be cancelled.
+func (s *Sweeper) orderedEndpoints(available []Interval, blocked []Interval) []Endpoint {
+	n := 2 * (len(available) + len(blocked))
+	if cap(s.endpoints) < n {
+		s.endpoints = make([]Endpoint, 0, n)
+	}
+	endpoints := s.endpoints[:0]
+
+	if endpointsInOrder(available) && endpointsInOrder(blocked) {
+		endpoints = mergeEndpoints(endpoints, available, blocked)
+	} else {
+		for _, a := range available {
+			endpoints = append(endpoints, Endpoint{Available, Start, a.Start}, Endpoint{Available, End, a.End})
+		}
+		for _, b := range blocked {
+			endpoints = append(endpoints, Endpoint{Blocked, Start, b.Start}, Endpoint{Blocked, End, b.End})
+		}
+		slices.SortFunc(endpoints, compareEndpoints)
+	}
+	s.endpoints = endpoints
+	return endpoints
+}
+
+// AppendSubstractBlockedIntervals is a shortcut for a one off Sweeper
+func AppendSubstractBlockedIntervals(dst []Interval, available []Interval, blocked []Interval) []Interval {
+	s := Sweeper{}
+	return s.AppendSubstractBlockedIntervals(dst, available, blocked)
+}
+
+// same order as EndpointsHeap.Less: by time, starts before ends so that adjacent intervals are merged
+func compareEndpoints(a, b Endpoint) int {
+	if c := a.Time.Compare(b.Time); c != 0 {
+		return c
+	}
+	if a.EndpointType == b.EndpointType {
+		return 0
+	}
+	if a.EndpointType == Start {
+		return -1
+	}
+	return 1
+}
+
+// reports if both the starts and the ends are sorted, which is the case for ordered disjoint intervals
+func endpointsInOrder(a []Interval) bool {
+	for k := 1; k < len(a); k++ {
+		if a[k].Start.Before(a[k-1].Start) || a[k].End.Before(a[k-1].End) {
+			return false
+		}
+	}
+	return true
+}
+
+// merges the four sorted endpoint sequences of the inputs
+func mergeEndpoints(dst []Endpoint, available []Interval, blocked []Interval) []Endpoint {
+	as, ae, bs, be := 0, 0, 0, 0
+	for ae < len(available) || be < len(blocked) {
+		next := Endpoint{}
+		found := false
+		consider := func(e Endpoint) {
+			if !found || compareEndpoints(e, next) < 0 {
+				next, found = e, true
+			}
+		}
+		if as < len(available) {
+			consider(Endpoint{Available, Start, available[as].Start})
+		}
+		if bs < len(blocked) {
+			consider(Endpoint{Blocked, Start, blocked[bs].Start})
+		}
+		if ae < len(available) {
+			consider(Endpoint{Available, End, available[ae].End})
+		}
+		if be < len(blocked) {
+			consider(Endpoint{Blocked, End, blocked[be].End})
+		}
+
+		switch {
+		case next.IntervalType == Available && next.EndpointType == Start:
+			as++
+		case next.IntervalType == Blocked && next.EndpointType == Start:
+			bs++
+		case next.IntervalType == Available:
+			ae++
+		default:
+			be++
+		}
+		dst = append(dst, next)
+	}
+	return dst
+}
+
+// the state machine of SubstractBlockedIntervals over already ordered endpoints: an available interval is open while
+// at least one available interval and no blocked interval is open
+func sweepEndpoints(dst []Interval, endpoints []Endpoint) []Interval {
+	dst, _ = sweepEndpointsContext(context.Background(), dst, endpoints, 0)
+	return dst
+}
+
+// sweepEndpoints checking ctx every checkEvery endpoints and failing with a *LimitError once more than maxResults
+// (if positive) intervals were appended
+func sweepEndpointsContext(ctx context.Context, dst []Interval, endpoints []Endpoint, maxResults int) ([]Interval, error) {
+	initial := len(dst)
+	availableOpenIntervals := 0
+	blockedOpenIntervals := 0
+	currentAvailableIntervalStart := time.Time{}
+	for k, e := range endpoints {
+		if k%checkEvery == checkEvery-1 {
+			if err := ctx.Err(); err != nil {
+				return dst, err
+			}
+		}
+
+		wasFree := availableOpenIntervals > 0 && blockedOpenIntervals == 0
+		availableOpenIntervals, blockedOpenIntervals = getNextCounts(e, availableOpenIntervals, blockedOpenIntervals)
+		isFree := availableOpenIntervals > 0 && blockedOpenIntervals == 0
+
+		if !wasFree && isFree {
+			currentAvailableIntervalStart = e.Time
+		}
+		if wasFree && !isFree && currentAvailableIntervalStart.Before(e.Time) {
+			if maxResults > 0 && len(dst)-initial == maxResults {
+				return dst, &LimitError{Limit: maxResults, What: "results"}
+			}
+			dst = append(dst, Interval{Start: currentAvailableIntervalStart, End: e.Time})
+		}
+	}
+	return dst, nil
+}
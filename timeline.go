@@ -0,0 +1,111 @@
+package time_intervals
+
+import (
+	"strings"
+	"time"
+)
+
+// TimelineRow is one labeled line of a rendered timeline
+type TimelineRow struct {
+	Label     string
+	Intervals []Interval
+}
+
+type TimelineOptions struct {
+	Resolution  time.Duration // time covered by one character, by default the axis is split in Width characters
+	Width       int           // used only when Resolution is not set, defaults to 60
+	Start       time.Time     // start of the axis, defaults to the earliest start of all rows
+	End         time.Time     // end of the axis, defaults to the latest end of all rows
+	TickEvery   int           // characters between two axis ticks, defaults to 10
+	LabelLayout string        // time layout of the tick labels, defaults to "15:04"
+}
+
+// RenderTimeline draws the rows on a shared time axis as text, one line per row followed by the axis.
+// A character is '#' when its time is fully covered by the intervals of the row and '+' when it is partially covered:
+//
+//	available |  ####    ###  |
+//	blocked   |    ##+        |
+//	           +---------+----
+//	           09:00     09:10
+func RenderTimeline(rows []TimelineRow, opts TimelineOptions) string {
+	start, end := opts.Start, opts.End
+	for _, r := range rows {
+		for _, i := range r.Intervals {
+			if opts.Start.IsZero() && (start.IsZero() || i.Start.Before(start)) {
+				start = i.Start
+			}
+			if opts.End.IsZero() && (end.IsZero() || i.End.After(end)) {
+				end = i.End
+			}
+		}
+	}
+	if !start.Before(end) {
+		end = start.Add(time.Minute)
+	}
+	if opts.Width <= 0 {
+		opts.Width = 60
+	}
+	res := opts.Resolution
+	if res <= 0 {
+		res = (end.Sub(start) + time.Duration(opts.Width) - 1) / time.Duration(opts.Width)
+	}
+	if opts.TickEvery <= 0 {
+		opts.TickEvery = 10
+	}
+	if opts.LabelLayout == "" {
+		opts.LabelLayout = "15:04"
+	}
+
+	columns := int((end.Sub(start) + res - 1) / res)
+	labelWidth := 0
+	for _, r := range rows {
+		if len(r.Label) > labelWidth {
+			labelWidth = len(r.Label)
+		}
+	}
+	pad := strings.Repeat(" ", labelWidth+2)
+
+	b := strings.Builder{}
+	for _, r := range rows {
+		merged := MergeAndReturnNonOverlappingIntervals(r.Intervals)
+		b.WriteString(r.Label + strings.Repeat(" ", labelWidth-len(r.Label)) + " |")
+		for c := 0; c < columns; c++ {
+			cell := Interval{Start: start.Add(time.Duration(c) * res), End: start.Add(time.Duration(c+1) * res)}
+			b.WriteByte(timelineCell(merged, cell))
+		}
+		b.WriteString("|\n")
+	}
+
+	axis := []byte(strings.Repeat("-", columns))
+	labels := []byte(strings.Repeat(" ", columns))
+	free := 0 // first column where the next label can be written without overlapping the previous one
+	for c := 0; c < columns; c += opts.TickEvery {
+		axis[c] = '+'
+		l := start.Add(time.Duration(c) * res).Format(opts.LabelLayout)
+		if c >= free {
+			for len(labels) < c+len(l) {
+				labels = append(labels, ' ')
+			}
+			copy(labels[c:], l)
+			free = c + len(l) + 1
+		}
+	}
+	b.WriteString(pad + string(axis) + "\n")
+	b.WriteString(strings.TrimRight(pad+string(labels), " ") + "\n")
+	return b.String()
+}
+
+func timelineCell(orderedDisjointIntervals []Interval, cell Interval) byte {
+	covered := time.Duration(0)
+	for _, i := range clipIntervals(orderedDisjointIntervals, cell) {
+		covered += i.End.Sub(i.Start)
+	}
+	switch {
+	case covered == 0:
+		return ' '
+	case covered == cell.End.Sub(cell.Start):
+		return '#'
+	default:
+		return '+'
+	}
+}
@@ -0,0 +1,43 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderTimeline(t *testing.T) {
+	available := []Interval{testInterval(9*60, 9*60+4), testInterval(9*60+8, 9*60+14)}
+	blocked := []Interval{testInterval(9*60+2, 9*60+5), testInterval(9*60+12, 9*60+12)}
+	partial := []Interval{{Start: testInterval(9*60+6, 0).Start.Add(30 * time.Second), End: testInterval(9*60+7, 0).Start}}
+
+	r := RenderTimeline([]TimelineRow{
+		{Label: "available", Intervals: available},
+		{Label: "blocked", Intervals: blocked},
+		{Label: "partial", Intervals: partial},
+	}, TimelineOptions{Resolution: time.Minute, TickEvery: 6})
+
+	expected := "" +
+		"available |####    ######|\n" +
+		"blocked   |  ###         |\n" +
+		"partial   |      +       |\n" +
+		"           +-----+-----+-\n" +
+		"           09:00 09:06 09:12\n"
+	assert.Equal(t, expected, r)
+}
+
+func Test_RenderTimeline_Bounds(t *testing.T) {
+	r := RenderTimeline([]TimelineRow{{Label: "a", Intervals: []Interval{testInterval(5, 10)}}}, TimelineOptions{
+		Start:       testInterval(0, 0).Start,
+		End:         testInterval(20, 0).Start,
+		Resolution:  2 * time.Minute,
+		LabelLayout: "04",
+	})
+
+	expected := "" +
+		"a |  +##     |\n" +
+		"   +---------\n" +
+		"   00\n"
+	assert.Equal(t, expected, r)
+}
@@ -0,0 +1,157 @@
+package time_intervals
+
+import (
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// WorkingHours are the hours worked on one day of the week, as wall clock offsets since the local midnight
+type WorkingHours struct {
+	Day   time.Weekday
+	Start time.Duration
+	End   time.Duration
+}
+
+// WorkSchedule describes business time: the weekly working hours are the available intervals and the holidays
+// the blocked ones, like in SubstractBlockedIntervals
+type WorkSchedule struct {
+	Weekly   []WorkingHours
+	Holidays []Interval
+	Location *time.Location // the working hours are wall clock times in this location, defaults to UTC
+}
+
+const week = 7 * 24 * time.Hour
+
+// WorkingDurationBetween returns the working time between a and b.
+// Whole weeks are not expanded: they are counted with the weekly total minus the working time lost to holidays, so
+// long spans cost as much as their holidays and daylight saving changes. The weeks with a change are expanded, so
+// working hours containing one count with the time that really passed.
+func WorkingDurationBetween(a, b time.Time, s WorkSchedule) time.Duration {
+	if !a.Before(b) {
+		return 0
+	}
+	return s.workingDuration(a, b, MergeAndReturnNonOverlappingIntervals(s.Holidays))
+}
+
+// AddWorkingDuration returns the time at which d of working time has passed since t, e.g. an SLA deadline
+func AddWorkingDuration(t time.Time, d time.Duration, s WorkSchedule) (time.Time, error) {
+	if d < 0 {
+		return time.Time{}, errors.New("Working duration can not be negative")
+	}
+	weekly := s.weeklyDuration()
+	if weekly <= 0 {
+		return time.Time{}, errors.New("Schedule has no working hours")
+	}
+	holidays := MergeAndReturnNonOverlappingIntervals(s.Holidays)
+
+	current := t
+	remaining := d
+	for remaining > 0 {
+		// jump over whole weeks while they surely end before the remaining working time is used up
+		if n := int(remaining / weekly); n > 0 {
+			next := current.In(s.location()).AddDate(0, 0, 7*n)
+			if w := s.workingDuration(current, next, holidays); w < remaining {
+				remaining -= w
+				current = next
+				continue
+			}
+		}
+
+		next := current.In(s.location()).AddDate(0, 0, 7)
+		free := SubstractBlockedIntervals(s.available(current, next), holidays)
+		if at, ok := TimeAtOffset(free, remaining); ok {
+			return at, nil
+		}
+		remaining -= TotalDuration(free)
+		current = next
+	}
+	return t, nil
+}
+
+func (s WorkSchedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+func (s WorkSchedule) weeklyDuration() time.Duration {
+	total := time.Duration(0)
+	for _, h := range s.Weekly {
+		if h.End > h.Start {
+			total += h.End - h.Start
+		}
+	}
+	return total
+}
+
+func (s WorkSchedule) workingDuration(from, to time.Time, mergedHolidays []Interval) time.Duration {
+	// whole weeks, leaving out one so that daylight saving changes can not push them past to
+	weeks := int(to.Sub(from)/week) - 1
+	total := time.Duration(0)
+	if weeks > 0 {
+		middle := from.In(s.location()).AddDate(0, 0, 7*weeks)
+		total += time.Duration(weeks)*s.weeklyDuration() - s.lostToHolidays(from, middle, mergedHolidays)
+		total += s.lostToZoneChanges(from, middle)
+		from = middle
+	}
+	return total + TotalDuration(SubstractBlockedIntervals(s.available(from, to), mergedHolidays))
+}
+
+// the difference between the working time that really passed and the weekly total in the weeks starting at from
+// that contain a change of the zone offset, only those weeks are expanded
+func (s WorkSchedule) lostToZoneChanges(from, to time.Time) time.Duration {
+	f := from.In(s.location())
+	diff := time.Duration(0)
+	lastWeek := -1
+	for t := f; ; {
+		_, change := t.ZoneBounds()
+		if change.IsZero() || !change.Before(to) {
+			return diff
+		}
+		t = change
+
+		w := int(change.Sub(f) / week)
+		for w > 0 && f.AddDate(0, 0, 7*w).After(change) {
+			w--
+		}
+		for !f.AddDate(0, 0, 7*(w+1)).After(change) {
+			w++
+		}
+		if w == lastWeek {
+			continue
+		}
+		lastWeek = w
+		weekStart, weekEnd := f.AddDate(0, 0, 7*w), f.AddDate(0, 0, 7*(w+1))
+		diff += TotalDuration(s.available(weekStart, weekEnd)) - s.weeklyDuration()
+	}
+}
+
+// the working time inside the holidays between from and to, only the holiday days are expanded
+func (s WorkSchedule) lostToHolidays(from, to time.Time, mergedHolidays []Interval) time.Duration {
+	lost := time.Duration(0)
+	for _, h := range clipIntervals(mergedHolidays, Interval{Start: from, End: to}) {
+		lost += TotalDuration(s.available(h.Start, h.End))
+	}
+	return lost
+}
+
+// expands the weekly working hours into ordered disjoint intervals between from and to
+func (s WorkSchedule) available(from, to time.Time) []Interval {
+	loc := s.location()
+	f := from.In(loc)
+	r := []Interval{}
+	for day := time.Date(f.Year(), f.Month(), f.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, h := range s.Weekly {
+			if h.Day != day.Weekday() || h.End <= h.Start {
+				continue
+			}
+			r = append(r, Interval{
+				Start: time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, int(h.Start), loc),
+				End:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, int(h.End), loc),
+			})
+		}
+	}
+	return clipIntervals(MergeAndReturnNonOverlappingIntervals(r), Interval{Start: from, End: to})
+}
@@ -0,0 +1,97 @@
+package time_intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func officeSchedule() WorkSchedule {
+	s := WorkSchedule{}
+	for d := time.Monday; d <= time.Friday; d++ {
+		s.Weekly = append(s.Weekly, WorkingHours{Day: d, Start: 9 * time.Hour, End: 17 * time.Hour})
+	}
+	return s
+}
+
+func Test_AddWorkingDuration(t *testing.T) {
+	s := officeSchedule()
+
+	// friday 16:00 + 8 business hours = monday 16:00
+	friday := time.Date(2018, 4, 13, 16, 0, 0, 0, time.UTC)
+	at, err := AddWorkingDuration(friday, 8*time.Hour, s)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 4, 16, 16, 0, 0, 0, time.UTC), at)
+
+	// with monday off it moves to tuesday
+	s.Holidays = []Interval{{Start: time.Date(2018, 4, 16, 0, 0, 0, 0, time.UTC), End: time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)}}
+	at, err = AddWorkingDuration(friday, 8*time.Hour, s)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 4, 17, 16, 0, 0, 0, time.UTC), at)
+
+	// the end of a working day is returned rather than the start of the next one
+	at, err = AddWorkingDuration(friday, time.Hour, s)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 4, 13, 17, 0, 0, 0, time.UTC), at)
+
+	_, err = AddWorkingDuration(friday, time.Hour, WorkSchedule{})
+	assert.Error(t, err)
+}
+
+func Test_WorkingDurationBetween(t *testing.T) {
+	s := officeSchedule()
+	monday := time.Date(2018, 4, 16, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 40*time.Hour, WorkingDurationBetween(monday, monday.AddDate(0, 0, 7), s))
+	assert.Equal(t, 6*time.Hour, WorkingDurationBetween(monday.Add(13*time.Hour), monday.Add(24*time.Hour+11*time.Hour), s))
+	assert.Equal(t, time.Duration(0), WorkingDurationBetween(monday.AddDate(0, 0, 1), monday, s))
+}
+
+func Test_WorkingTime_LongSpans(t *testing.T) {
+	s := officeSchedule()
+	s.Location, _ = time.LoadLocation("Europe/Bucharest")
+	if s.Location == nil {
+		s.Location = time.UTC
+	}
+	start := time.Date(2018, 1, 3, 11, 30, 0, 0, s.Location)
+	for m := 0; m < 12; m++ {
+		day := time.Date(2018, time.Month(m+1), 10+m, 0, 0, 0, 0, s.Location)
+		s.Holidays = append(s.Holidays, Interval{Start: day.Add(12 * time.Hour), End: day.AddDate(0, 0, 2)})
+	}
+
+	// compare with expanding every day
+	for _, end := range []time.Time{start.AddDate(0, 0, 20), start.AddDate(0, 5, 3), start.AddDate(2, 0, 0)} {
+		expected := TotalDuration(SubstractBlockedIntervals(s.available(start, end), s.Holidays))
+		assert.Equal(t, expected, WorkingDurationBetween(start, end, s), "until %v", end)
+	}
+
+	for _, d := range []time.Duration{3 * time.Hour, 100 * time.Hour, 2000*time.Hour + 17*time.Minute} {
+		at, err := AddWorkingDuration(start, d, s)
+		assert.NoError(t, err)
+		assert.Equal(t, d, WorkingDurationBetween(start, at, s), "adding %v", d)
+		assert.Equal(t, d, TotalDuration(SubstractBlockedIntervals(s.available(start, at), s.Holidays)), "adding %v", d)
+	}
+}
+
+func Test_WorkingTime_LongSpans_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// around the clock, so every daylight saving change is inside the working hours
+	s := WorkSchedule{Location: berlin}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		s.Weekly = append(s.Weekly, WorkingHours{Day: d, Start: 0, End: 24 * time.Hour})
+	}
+	start := time.Date(2018, 3, 10, 12, 0, 0, 0, berlin)
+
+	for _, end := range []time.Time{start.AddDate(0, 0, 20), start.AddDate(0, 0, 40), start.AddDate(1, 0, 0), start.AddDate(2, 1, 0)} {
+		assert.Equal(t, end.Sub(start), WorkingDurationBetween(start, end, s), "until %v", end)
+	}
+	for _, d := range []time.Duration{100 * time.Hour, 1000 * time.Hour, 20000 * time.Hour} {
+		at, err := AddWorkingDuration(start, d, s)
+		assert.NoError(t, err)
+		assert.Equal(t, d, at.Sub(start), "adding %v", d)
+	}
+}